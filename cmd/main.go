@@ -1,9 +1,8 @@
 package main
 
 import (
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"os"
 	"os/signal"
 	"syscall"
@@ -13,12 +12,12 @@ import (
 	"github.com/weni/whatsapp-router/config"
 	"github.com/weni/whatsapp-router/logger"
 	"github.com/weni/whatsapp-router/metric"
-	"github.com/weni/whatsapp-router/models"
 	"github.com/weni/whatsapp-router/repositories"
 	"github.com/weni/whatsapp-router/servers/grpc"
 	"github.com/weni/whatsapp-router/servers/http"
 	"github.com/weni/whatsapp-router/services"
 	"github.com/weni/whatsapp-router/storage"
+	"github.com/weni/whatsapp-router/utils"
 	"go.mongodb.org/mongo-driver/mongo"
 )
 
@@ -27,15 +26,30 @@ func main() {
 	defer storage.CloseDB(db)
 	logger.Info("Starting application...")
 
-	initAuthToken(db)
+	if config.GetConfig().OIDC.TokenAudience() == "" {
+		logger.Error("OIDC_AUDIENCE or OIDC_CLIENT_ID is required to validate the keycloak access tokens")
+		os.Exit(1)
+	}
+	if _, err := utils.ParseIPNets(config.GetConfig().App.TrustedProxies); err != nil {
+		logger.Error(fmt.Sprintf("invalid APP_TRUSTED_PROXIES: %s", err))
+		os.Exit(1)
+	}
+
+	initIndexes(db)
+	tokenStore := services.NewTokenStore()
+	accountService := services.NewWhatsappAccountService(repositories.NewWhatsappAccountRepositoryDb(db))
+	tokenManager := initAuthToken(db, tokenStore, accountService)
+	initCourierRetry(db)
+	initTemplateSync(db, tokenStore, tokenManager, accountService)
 	var err error
 	metrics, err := metric.NewPrometheusService()
 	if err != nil {
 		logger.Error(err.Error())
 		os.Exit(1)
 	}
+	initBindingExpiration(db, metrics)
 
-	httpServer := http.NewServer(db, metrics)
+	httpServer := http.NewServer(db, metrics, tokenStore, tokenManager, accountService)
 	if err := httpServer.Start(); err != nil {
 		logger.Error(fmt.Sprintf("Server startup failed: %v", err))
 		os.Exit(1)
@@ -54,72 +68,163 @@ func main() {
 
 }
 
-const tokenUpdateInterval = 12
-
-func initAuthToken(db *mongo.Database) {
-	configRepo := repositories.NewConfigRepository(db)
-	configService := services.NewConfigService(configRepo)
-	conf, err := configService.GetConfig()
-	if err != nil {
-		logger.Error(fmt.Sprintf("Error getting config with whatsapp auth token: %s", err))
+func initIndexes(db *mongo.Database) {
+	channelRepo := repositories.NewChannelRepositoryDb(db)
+	if err := channelRepo.CreateIndexes(); err != nil {
+		logger.Error(fmt.Sprintf("Error creating channel indexes: %s", err))
 		os.Exit(1)
 	}
-	if conf == nil {
-		whatsappService := services.NewWhatsappService()
-		res, err := whatsappService.Login()
-		if err != nil {
-			logger.Error(err.Error())
+	contactRepo := repositories.NewContactRepositoryDb(db)
+	if err := contactRepo.CreateIndexes(); errors.Is(err, repositories.ErrDuplicate) {
+		logger.Error(fmt.Sprintf("Error creating contact indexes, the former indexes are kept until the contacts with duplicated urns in an account are merged or removed and the router is restarted: %s", err))
+	} else if err != nil {
+		logger.Error(fmt.Sprintf("Error creating contact indexes: %s", err))
+		os.Exit(1)
+	}
+	bindingRepo := repositories.NewContactBindingRepositoryDb(db)
+	if err := bindingRepo.CreateIndexes(); err != nil {
+		logger.Error(fmt.Sprintf("Error creating contact binding indexes: %s", err))
+		os.Exit(1)
+	}
+	accountRepo := repositories.NewWhatsappAccountRepositoryDb(db)
+	if err := accountRepo.CreateIndexes(); err != nil {
+		logger.Error(fmt.Sprintf("Error creating whatsapp account indexes: %s", err))
+		os.Exit(1)
+	}
+	templateRepo := repositories.NewTemplateRepositoryDb(db)
+	if err := templateRepo.CreateIndexes(); err != nil {
+		logger.Error(fmt.Sprintf("Error creating template indexes: %s", err))
+		os.Exit(1)
+	}
+	messageRepo := repositories.NewMessageRepositoryDb(db)
+	if err := messageRepo.CreateIndexes(config.GetConfig().App.MessageTTL); err != nil {
+		logger.Error(fmt.Sprintf("Error creating message indexes: %s", err))
+		os.Exit(1)
+	}
+	processedMessageRepo := repositories.NewProcessedMessageRepositoryDb(db)
+	if err := processedMessageRepo.CreateIndexes(config.GetConfig().App.DedupTTL); err != nil {
+		logger.Error(fmt.Sprintf("Error creating processed message indexes: %s", err))
+		os.Exit(1)
+	}
+}
+
+// tokenCheckInterval is how often the expiration of the whatsapp auth token
+// is checked.
+const tokenCheckInterval = time.Minute
+
+func initAuthToken(db *mongo.Database, tokenStore services.TokenStore, accountService services.WhatsappAccountService) services.TokenManager {
+	// cloud api accounts use a permanent access token
+	var tokenManager services.TokenManager
+	account := services.DefaultWhatsappAccount()
+	if !account.IsCloud() {
+		configRepo := repositories.NewConfigRepository(db)
+		configService := services.NewConfigService(configRepo)
+		defaultTokenManager := services.NewTokenManager(account, tokenStore, configService)
+		if err := defaultTokenManager.Start(); err != nil {
+			logger.Error(fmt.Sprintf("Error getting whatsapp auth token: %s", err))
 			os.Exit(1)
 		}
-		var login services.LoginWhatsapp
-		if err := json.NewDecoder(res.Body).Decode(&login); err != nil {
-			logger.Error(err.Error())
-			os.Exit(1)
+		tokenManager = defaultTokenManager
+	}
+
+	s := gocron.NewScheduler(time.UTC)
+	s.Every(tokenCheckInterval).
+		SingletonMode().
+		Do(func() {
+			if tokenManager != nil {
+				tokenManager.RefreshIfExpiring()
+			}
+			accountService.RefreshTokens()
+		})
+
+	s.StartAsync()
+	return tokenManager
+}
+
+func initCourierRetry(db *mongo.Database) {
+	forwardRepo := repositories.NewForwardRepositoryDb(db)
+	courierService := services.NewCourierService(forwardRepo)
+
+	s := gocron.NewScheduler(time.UTC)
+	s.Every(config.GetConfig().App.CourierRetry.Interval).
+		SingletonMode().
+		Do(courierService.RetryPendingMessages)
+
+	s.StartAsync()
+}
+
+// initTemplateSync schedules the sync of the message templates of the default
+// and of the other whatsapp accounts, every APP_TEMPLATE_SYNC_INTERVAL.
+func initTemplateSync(db *mongo.Database, tokenStore services.TokenStore, tokenManager services.TokenManager, accountService services.WhatsappAccountService) {
+	interval := config.GetConfig().App.TemplateSync
+	if interval <= 0 {
+		return
+	}
+	templateService := services.NewTemplateService(repositories.NewTemplateRepositoryDb(db))
+	defaultService := services.NewAccountWhatsappService(services.DefaultWhatsappAccount(), tokenStore, tokenManager)
+	syncTemplates := func(account string, ws services.WhatsappService) {
+		synced, err := templateService.SyncTemplates(account, ws)
+		if errors.Is(err, services.ErrTemplatesNotSupported) {
+			logger.Debug(fmt.Sprintf("templates of whatsapp account %q not synced: %s", account, err))
+			return
 		}
-		conf, err = configService.CreateOrUpdate(&models.Config{Token: login.Users[0].Token})
 		if err != nil {
-			logger.Error(err.Error())
-			os.Exit(1)
+			logger.Error(fmt.Sprintf("Error syncing templates of whatsapp account %q: %s", account, err))
+			return
 		}
+		logger.Debug(fmt.Sprintf("%d templates of whatsapp account %q synced", synced, account))
 	}
-	config.UpdateAuthToken(conf.Token)
-
-	whatsappService := services.NewWhatsappService()
 
 	s := gocron.NewScheduler(time.UTC)
-	s.Every(tokenUpdateInterval).
-		Hour().
-		StartAt(time.Now().Add(time.Hour * tokenUpdateInterval)).
+	s.Every(interval).
+		SingletonMode().
 		Do(func() {
-			res, err := whatsappService.Login()
-			if err != nil {
-				logger.Error(err.Error())
-				return
-			}
-
-			var login services.LoginWhatsapp
-			bdBytes, err := io.ReadAll(res.Body)
-			defer res.Body.Close()
+			syncTemplates("", defaultService)
+			accounts, err := accountService.ListAccounts()
 			if err != nil {
 				logger.Error(err.Error())
 				return
 			}
-			if res.StatusCode != 200 {
-				logger.Error(fmt.Sprintf("Couldn't update token: %s, %s", res.Status, string(bdBytes)))
-				return
+			for _, account := range accounts {
+				ws, err := accountService.WhatsappService(account.Name)
+				if err != nil {
+					logger.Error(err.Error())
+					continue
+				}
+				syncTemplates(account.Name, ws)
 			}
+		})
 
-			if err := json.Unmarshal(bdBytes, &login); err != nil {
-				logger.Error(err.Error())
-				return
-			}
-			newToken := login.Users[0].Token
+	s.StartAsync()
+}
 
-			configService.CreateOrUpdate(&models.Config{Token: newToken})
+// initBindingExpiration schedules the expiration of the binding of contacts to
+// the channels with a binding TTL or inactivity timeout, every
+// APP_BINDING_EXPIRATION_INTERVAL.
+func initBindingExpiration(db *mongo.Database, metrics *metric.Service) {
+	interval := config.GetConfig().App.BindingExpiry
+	if interval <= 0 {
+		return
+	}
+	contactRepo := repositories.NewContactRepositoryDb(db)
+	bindingRepo := repositories.NewContactBindingRepositoryDb(db)
+	bindingExpiration := services.NewBindingExpiration(
+		services.NewChannelService(
+			repositories.NewChannelRepositoryDb(db),
+			contactRepo,
+			repositories.NewWhatsappAccountRepositoryDb(db),
+			bindingRepo,
+			metrics,
+		),
+		services.NewContactService(contactRepo),
+		services.NewContactBindingService(bindingRepo),
+		metrics,
+	)
 
-			config.UpdateAuthToken(newToken)
-			logger.Info("Whatsapp token updated")
-		})
+	s := gocron.NewScheduler(time.UTC)
+	s.Every(interval).
+		SingletonMode().
+		Do(bindingExpiration.ExpireBindings)
 
 	s.StartAsync()
 }
@@ -3,11 +3,12 @@ package config
 import (
 	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"strings"
+	"sync"
+	"time"
 
 	"github.com/joeshaw/envdecode"
-	"github.com/weni/whatsapp-router/utils"
 )
 
 type Config struct {
@@ -18,11 +19,25 @@ type Config struct {
 }
 
 type App struct {
-	HttpPort       int32  `env:"APP_HTTP_PORT,default=9000"`
-	GRPCPort       int32  `env:"APP_GRPC_PORT,default=7000"`
-	CourierBaseURL string `env:"APP_COURIER_BASE_URL,default=http://localhost:8000/c/wa"`
-	SentryDSN      string `env:"APP_SENTRY_DSN"`
-	LogLevel       string `env:"APP_LOG_LEVEL,default=debug"`
+	HttpPort        int32         `env:"APP_HTTP_PORT,default=9000"`
+	GRPCPort        int32         `env:"APP_GRPC_PORT,default=7000"`
+	CourierBaseURL  string        `env:"APP_COURIER_BASE_URL,default=http://localhost:8000/c/wa"`
+	SentryDSN       string        `env:"APP_SENTRY_DSN"`
+	LogLevel        string        `env:"APP_LOG_LEVEL,default=debug"`
+	DedupTTL        time.Duration `env:"APP_DEDUP_TTL,default=24h"`
+	MessageTTL      time.Duration `env:"APP_MESSAGE_TTL,default=720h"`
+	OutboundScoping string        `env:"APP_OUTBOUND_SCOPING,default=reject"`
+	TrustedProxies  []string      `env:"APP_TRUSTED_PROXIES"`
+	TemplateSync    time.Duration `env:"APP_TEMPLATE_SYNC_INTERVAL,default=1h"`
+	BindingExpiry   time.Duration `env:"APP_BINDING_EXPIRATION_INTERVAL,default=1m"`
+	CourierRetry    CourierRetry
+}
+
+type CourierRetry struct {
+	Interval    time.Duration `env:"APP_COURIER_RETRY_INTERVAL,default=10s"`
+	BaseDelay   time.Duration `env:"APP_COURIER_RETRY_BASE_DELAY,default=10s"`
+	MaxDelay    time.Duration `env:"APP_COURIER_RETRY_MAX_DELAY,default=1h"`
+	MaxAttempts int           `env:"APP_COURIER_RETRY_MAX_ATTEMPTS,default=10"`
 }
 
 type DB struct {
@@ -31,50 +46,83 @@ type DB struct {
 }
 
 type Whatsapp struct {
-	BaseURL        string `env:"WPP_BASEURL,required"`
-	Username       string `env:"WPP_USERNAME,required"`
-	Password       string `env:"WPP_PASSWORD,required"`
-	WelcomeMessage string `env:"WPP_CONFIRMATION_MESSAGE,default=Olá, bem vindo ao WhatsApp Demo, para iniciar um fluxo de mensagens envie a *palavra chave* do fluxo que deseja iniciar 👀"`
+	Provider          string        `env:"WPP_PROVIDER,default=on_premise"`
+	BaseURL           string        `env:"WPP_BASEURL,required"`
+	Username          string        `env:"WPP_USERNAME"`
+	Password          string        `env:"WPP_PASSWORD"`
+	PhoneNumberID     string        `env:"WPP_PHONE_NUMBER_ID"`
+	AccessToken       string        `env:"WPP_ACCESS_TOKEN"`
+	VerifyToken       string        `env:"WPP_VERIFY_TOKEN"`
+	WebhookSecret     string        `env:"WPP_WEBHOOK_SECRET"`
+	WebhookAllowedIPs []string      `env:"WPP_WEBHOOK_ALLOWED_IPS"`
+	WelcomeMessage    string        `env:"WPP_CONFIRMATION_MESSAGE,default=Olá, bem vindo ao WhatsApp Demo, para iniciar um fluxo de mensagens envie a *palavra chave* do fluxo que deseja iniciar 👀"`
+	StopKeywords      []string      `env:"WPP_STOP_KEYWORDS,default=sair;stop"`
+	FarewellMessage   string        `env:"WPP_FAREWELL_MESSAGE,default=Você saiu do WhatsApp Demo. Para voltar envie o *token* de um canal 👋"`
+	TokenRefreshAhead time.Duration `env:"WPP_TOKEN_REFRESH_AHEAD,default=24h"`
+	RateLimit         RateLimit
+	Reengagement      Reengagement
+	ChannelList       ChannelList
+}
+
+type RateLimit struct {
+	Rate      float64       `env:"WPP_RATE_LIMIT,default=80"`
+	Burst     int           `env:"WPP_RATE_LIMIT_BURST,default=20"`
+	QueueSize int           `env:"WPP_RATE_LIMIT_QUEUE_SIZE,default=1000"`
+	MaxWait   time.Duration `env:"WPP_RATE_LIMIT_MAX_WAIT,default=5s"`
+}
+
+type Reengagement struct {
+	Template  string `env:"WPP_REENGAGEMENT_TEMPLATE"`
+	Language  string `env:"WPP_REENGAGEMENT_LANGUAGE,default=pt_BR"`
+	Namespace string `env:"WPP_REENGAGEMENT_NAMESPACE"`
+}
+
+type ChannelList struct {
+	Keywords     []string `env:"WPP_CHANNEL_LIST_KEYWORDS,default=canais;channels"`
+	Size         int      `env:"WPP_CHANNEL_LIST_SIZE,default=10"`
+	Body         string   `env:"WPP_CHANNEL_LIST_BODY,default=Escolha o canal que deseja usar 👇"`
+	Button       string   `env:"WPP_CHANNEL_LIST_BUTTON,default=Canais"`
+	EmptyMessage string   `env:"WPP_CHANNEL_LIST_EMPTY_MESSAGE,default=Você ainda não usou nenhum canal. Envie o *token* de um canal para começar 👀"`
 }
 
 type OIDC struct {
-	Realm string `env:"OIDC_REALM,default=gocloak"`
-	Host  string `env:"OIDC_HOST,default=http://localhost:8080"`
+	Realm        string        `env:"OIDC_REALM,default=gocloak"`
+	Host         string        `env:"OIDC_HOST,default=http://localhost:8080"`
+	Issuer       string        `env:"OIDC_ISSUER"`
+	ClientID     string        `env:"OIDC_CLIENT_ID,default=whatsapp-router"`
+	Audience     string        `env:"OIDC_AUDIENCE"`
+	JWKSCacheTTL time.Duration `env:"OIDC_JWKS_CACHE_TTL,default=10m"`
 }
 
-var appConf *Config
+// IssuerURL returns the issuer of the access tokens of the realm, by default
+// the realm url of the keycloak host.
+func (o OIDC) IssuerURL() string {
+	if o.Issuer != "" {
+		return strings.TrimRight(o.Issuer, "/")
+	}
+	return strings.TrimRight(o.Host, "/") + "/auth/realms/" + o.Realm
+}
 
-var authToken string
+// TokenAudience returns the audience the access tokens must be issued to, by
+// default the keycloak client of the router.
+func (o OIDC) TokenAudience() string {
+	if o.Audience != "" {
+		return o.Audience
+	}
+	return o.ClientID
+}
+
+var appConf *Config
+var loadConf sync.Once
 
 func GetConfig() *Config {
-	if appConf == nil {
+	loadConf.Do(func() {
 		log.Println("loading config")
 		appConf = &Config{}
 		if err := envdecode.Decode(appConf); err != nil {
 			log.Println(fmt.Sprintf("Failed to decode and load environment variables: %v", err.Error()))
 			os.Exit(1)
 		}
-	}
+	})
 	return appConf
 }
-
-func GetAuthToken() string {
-	if authToken == "" {
-		req, err := http.NewRequest("GET", "v1/users/login", nil)
-		if err != nil {
-			log.Println(err.Error())
-			return ""
-		}
-		client := utils.GetHTTPClient()
-		_, err = client.Do(req)
-		if err != nil {
-			log.Println(err.Error())
-			return ""
-		}
-	}
-	return authToken
-}
-
-func UpdateAuthToken(token string) {
-	authToken = token
-}
@@ -40,6 +40,43 @@ func NewContactActivated(channel string) *ContactActivated {
 	return &ContactActivated{Channel: channel}
 }
 
+// DuplicateMessage represents a duplicated incoming message dropped metric.
+type DuplicateMessage struct{}
+
+// DuplicateMessage returns new metric struct value representation.
+func NewDuplicateMessage() *DuplicateMessage {
+	return &DuplicateMessage{}
+}
+
+// WebhookRejection represents a rejected incoming webhook metric.
+type WebhookRejection struct {
+	Account string
+	Reason  string
+}
+
+// WebhookRejection returns new metric struct value representation.
+func NewWebhookRejection(account, reason string) *WebhookRejection {
+	return &WebhookRejection{Account: account, Reason: reason}
+}
+
+// Statuses of outbound messages.
+const (
+	OutboundSent      = "sent"
+	OutboundRejected  = "rejected"
+	OutboundThrottled = "throttled"
+)
+
+// OutboundMessage represents a message sent by a channel metric.
+type OutboundMessage struct {
+	Channel string
+	Status  string
+}
+
+// OutboundMessage returns new metric struct value representation.
+func NewOutboundMessage(channel, status string) *OutboundMessage {
+	return &OutboundMessage{Channel: channel, Status: status}
+}
+
 // Metric encapsulates interface metric definitions
 type Metric interface {
 	SaveChannelCreation(m *ChannelCreation)
@@ -47,4 +84,7 @@ type Metric interface {
 	SaveContactActivation(m *ContactActivation)
 	IncContactActivated(m *ContactActivated)
 	DecContactActivated(m *ContactActivated)
+	SaveDuplicateMessage(m *DuplicateMessage)
+	SaveWebhookRejection(m *WebhookRejection)
+	SaveOutboundMessage(m *OutboundMessage)
 }
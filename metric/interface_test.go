@@ -22,4 +22,13 @@ func TestMetricInterface(t *testing.T) {
 	contactActivated := NewContactActivated(chanelUUID)
 	assert.NotNil(t, contactActivated)
 
+	duplicateMessage := NewDuplicateMessage()
+	assert.NotNil(t, duplicateMessage)
+
+	webhookRejection := NewWebhookRejection("acme", "signature")
+	assert.NotNil(t, webhookRejection)
+
+	outboundMessage := NewOutboundMessage(chanelUUID, OutboundSent)
+	assert.NotNil(t, outboundMessage)
+
 }
@@ -8,6 +8,9 @@ type Service struct {
 	contactsMessages    *prometheus.CounterVec
 	contactsActivations *prometheus.CounterVec
 	contactsActivated   *prometheus.GaugeVec
+	duplicateMessages   prometheus.Counter
+	webhookRejections   *prometheus.CounterVec
+	outboundMessages    *prometheus.CounterVec
 }
 
 // NewPrometheusService returns a new metric service
@@ -32,11 +35,29 @@ func NewPrometheusService() (*Service, error) {
 		Help: "Contact activated gauge labeled by channel",
 	}, []string{"channel"})
 
+	duplicateMessages := prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "duplicate_messages",
+		Help: "Duplicated incoming messages dropped counter",
+	})
+
+	webhookRejections := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "webhook_rejections",
+		Help: "Rejected incoming webhooks counter labeled by whatsapp account and reason",
+	}, []string{"account", "reason"})
+
+	outboundMessages := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "outbound_messages",
+		Help: "Messages sent by channels counter labeled by channel and status",
+	}, []string{"channel", "status"})
+
 	s := &Service{
 		channelsCreations:   channelsCreations,
 		contactsMessages:    contactsMessages,
 		contactsActivations: contactsActivations,
 		contactsActivated:   contactsActivated,
+		duplicateMessages:   duplicateMessages,
+		webhookRejections:   webhookRejections,
+		outboundMessages:    outboundMessages,
 	}
 
 	err := prometheus.Register(s.channelsCreations)
@@ -59,6 +80,21 @@ func NewPrometheusService() (*Service, error) {
 		return nil, err
 	}
 
+	err = prometheus.Register(s.duplicateMessages)
+	if err != nil && err.Error() != "duplicate metrics collector registration attempted" {
+		return nil, err
+	}
+
+	err = prometheus.Register(s.webhookRejections)
+	if err != nil && err.Error() != "duplicate metrics collector registration attempted" {
+		return nil, err
+	}
+
+	err = prometheus.Register(s.outboundMessages)
+	if err != nil && err.Error() != "duplicate metrics collector registration attempted" {
+		return nil, err
+	}
+
 	return s, nil
 }
 
@@ -86,3 +122,18 @@ func (s *Service) IncContactActivated(ca *ContactActivated) {
 func (s *Service) DecContactActivated(ca *ContactActivated) {
 	s.contactsActivated.WithLabelValues(ca.Channel).Dec()
 }
+
+// receive a *metric.DuplicateMessage metric and save to a Counter metric type.
+func (s *Service) SaveDuplicateMessage(dm *DuplicateMessage) {
+	s.duplicateMessages.Inc()
+}
+
+// receive a *metric.WebhookRejection metric and save to a Counter metric type.
+func (s *Service) SaveWebhookRejection(wr *WebhookRejection) {
+	s.webhookRejections.WithLabelValues(wr.Account, wr.Reason).Inc()
+}
+
+// receive a *metric.OutboundMessage metric and save to a Counter metric type.
+func (s *Service) SaveOutboundMessage(om *OutboundMessage) {
+	s.outboundMessages.WithLabelValues(om.Channel, om.Status).Inc()
+}
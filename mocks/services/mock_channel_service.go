@@ -66,6 +66,35 @@ func (mr *MockChannelServiceMockRecorder) CreateChannelDefault(arg0 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateChannelDefault", reflect.TypeOf((*MockChannelService)(nil).CreateChannelDefault), arg0)
 }
 
+// DeleteChannel mocks base method.
+func (m *MockChannelService) DeleteChannel(arg0 context.Context, arg1 *pb.DeleteChannelRequest) (*pb.DeleteChannelResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannel", arg0, arg1)
+	ret0, _ := ret[0].(*pb.DeleteChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteChannel indicates an expected call of DeleteChannel.
+func (mr *MockChannelServiceMockRecorder) DeleteChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannel", reflect.TypeOf((*MockChannelService)(nil).DeleteChannel), arg0, arg1)
+}
+
+// DeleteChannelDefault mocks base method.
+func (m *MockChannelService) DeleteChannelDefault(arg0, arg1 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteChannelDefault", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteChannelDefault indicates an expected call of DeleteChannelDefault.
+func (mr *MockChannelServiceMockRecorder) DeleteChannelDefault(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteChannelDefault", reflect.TypeOf((*MockChannelService)(nil).DeleteChannelDefault), arg0, arg1)
+}
+
 // FindChannel mocks base method.
 func (m *MockChannelService) FindChannel(arg0 *models.Channel) (*models.Channel, error) {
 	m.ctrl.T.Helper()
@@ -81,6 +110,21 @@ func (mr *MockChannelServiceMockRecorder) FindChannel(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChannel", reflect.TypeOf((*MockChannelService)(nil).FindChannel), arg0)
 }
 
+// FindChannelByCredential mocks base method.
+func (m *MockChannelService) FindChannelByCredential(arg0 string) (*models.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindChannelByCredential", arg0)
+	ret0, _ := ret[0].(*models.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindChannelByCredential indicates an expected call of FindChannelByCredential.
+func (mr *MockChannelServiceMockRecorder) FindChannelByCredential(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChannelByCredential", reflect.TypeOf((*MockChannelService)(nil).FindChannelByCredential), arg0)
+}
+
 // FindChannelById mocks base method.
 func (m *MockChannelService) FindChannelById(arg0 string) (*models.Channel, error) {
 	m.ctrl.T.Helper()
@@ -110,3 +154,138 @@ func (mr *MockChannelServiceMockRecorder) FindChannelByToken(arg0 interface{}) *
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindChannelByToken", reflect.TypeOf((*MockChannelService)(nil).FindChannelByToken), arg0)
 }
+
+// GetChannel mocks base method.
+func (m *MockChannelService) GetChannel(arg0 context.Context, arg1 *pb.GetChannelRequest) (*pb.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetChannel", arg0, arg1)
+	ret0, _ := ret[0].(*pb.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetChannel indicates an expected call of GetChannel.
+func (mr *MockChannelServiceMockRecorder) GetChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetChannel", reflect.TypeOf((*MockChannelService)(nil).GetChannel), arg0, arg1)
+}
+
+// ListChannels mocks base method.
+func (m *MockChannelService) ListChannels(arg0 context.Context, arg1 *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChannels", arg0, arg1)
+	ret0, _ := ret[0].(*pb.ListChannelsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChannels indicates an expected call of ListChannels.
+func (mr *MockChannelServiceMockRecorder) ListChannels(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChannels", reflect.TypeOf((*MockChannelService)(nil).ListChannels), arg0, arg1)
+}
+
+// ListChannelsDefault mocks base method.
+func (m *MockChannelService) ListChannelsDefault(arg0, arg1 int64) ([]*models.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListChannelsDefault", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListChannelsDefault indicates an expected call of ListChannelsDefault.
+func (mr *MockChannelServiceMockRecorder) ListChannelsDefault(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListChannelsDefault", reflect.TypeOf((*MockChannelService)(nil).ListChannelsDefault), arg0, arg1)
+}
+
+// RotateChannelCredential mocks base method.
+func (m *MockChannelService) RotateChannelCredential(arg0 context.Context, arg1 *pb.RotateChannelCredentialRequest) (*pb.ChannelResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateChannelCredential", arg0, arg1)
+	ret0, _ := ret[0].(*pb.ChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateChannelCredential indicates an expected call of RotateChannelCredential.
+func (mr *MockChannelServiceMockRecorder) RotateChannelCredential(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateChannelCredential", reflect.TypeOf((*MockChannelService)(nil).RotateChannelCredential), arg0, arg1)
+}
+
+// RotateChannelCredentialDefault mocks base method.
+func (m *MockChannelService) RotateChannelCredentialDefault(arg0 string) (*models.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateChannelCredentialDefault", arg0)
+	ret0, _ := ret[0].(*models.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateChannelCredentialDefault indicates an expected call of RotateChannelCredentialDefault.
+func (mr *MockChannelServiceMockRecorder) RotateChannelCredentialDefault(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateChannelCredentialDefault", reflect.TypeOf((*MockChannelService)(nil).RotateChannelCredentialDefault), arg0)
+}
+
+// RotateChannelToken mocks base method.
+func (m *MockChannelService) RotateChannelToken(arg0 context.Context, arg1 *pb.RotateChannelTokenRequest) (*pb.ChannelResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateChannelToken", arg0, arg1)
+	ret0, _ := ret[0].(*pb.ChannelResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateChannelToken indicates an expected call of RotateChannelToken.
+func (mr *MockChannelServiceMockRecorder) RotateChannelToken(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateChannelToken", reflect.TypeOf((*MockChannelService)(nil).RotateChannelToken), arg0, arg1)
+}
+
+// RotateChannelTokenDefault mocks base method.
+func (m *MockChannelService) RotateChannelTokenDefault(arg0 string) (*models.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RotateChannelTokenDefault", arg0)
+	ret0, _ := ret[0].(*models.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RotateChannelTokenDefault indicates an expected call of RotateChannelTokenDefault.
+func (mr *MockChannelServiceMockRecorder) RotateChannelTokenDefault(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RotateChannelTokenDefault", reflect.TypeOf((*MockChannelService)(nil).RotateChannelTokenDefault), arg0)
+}
+
+// UpdateChannel mocks base method.
+func (m *MockChannelService) UpdateChannel(arg0 context.Context, arg1 *pb.UpdateChannelRequest) (*pb.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChannel", arg0, arg1)
+	ret0, _ := ret[0].(*pb.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChannel indicates an expected call of UpdateChannel.
+func (mr *MockChannelServiceMockRecorder) UpdateChannel(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannel", reflect.TypeOf((*MockChannelService)(nil).UpdateChannel), arg0, arg1)
+}
+
+// UpdateChannelDefault mocks base method.
+func (m *MockChannelService) UpdateChannelDefault(arg0 *models.Channel) (*models.Channel, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateChannelDefault", arg0)
+	ret0, _ := ret[0].(*models.Channel)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateChannelDefault indicates an expected call of UpdateChannelDefault.
+func (mr *MockChannelServiceMockRecorder) UpdateChannelDefault(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateChannelDefault", reflect.TypeOf((*MockChannelService)(nil).UpdateChannelDefault), arg0)
+}
@@ -0,0 +1,64 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./services/contact_binding_service.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/weni/whatsapp-router/models"
+)
+
+// MockContactBindingService is a mock of ContactBindingService interface.
+type MockContactBindingService struct {
+	ctrl     *gomock.Controller
+	recorder *MockContactBindingServiceMockRecorder
+}
+
+// MockContactBindingServiceMockRecorder is the mock recorder for MockContactBindingService.
+type MockContactBindingServiceMockRecorder struct {
+	mock *MockContactBindingService
+}
+
+// NewMockContactBindingService creates a new mock instance.
+func NewMockContactBindingService(ctrl *gomock.Controller) *MockContactBindingService {
+	mock := &MockContactBindingService{ctrl: ctrl}
+	mock.recorder = &MockContactBindingServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockContactBindingService) EXPECT() *MockContactBindingServiceMockRecorder {
+	return m.recorder
+}
+
+// ListBindings mocks base method.
+func (m *MockContactBindingService) ListBindings(urn, account string, page, limit int64) ([]*models.ContactBinding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListBindings", urn, account, page, limit)
+	ret0, _ := ret[0].([]*models.ContactBinding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListBindings indicates an expected call of ListBindings.
+func (mr *MockContactBindingServiceMockRecorder) ListBindings(urn, account, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListBindings", reflect.TypeOf((*MockContactBindingService)(nil).ListBindings), urn, account, page, limit)
+}
+
+// RecordBinding mocks base method.
+func (m *MockContactBindingService) RecordBinding(arg0 *models.ContactBinding) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordBinding", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordBinding indicates an expected call of RecordBinding.
+func (mr *MockContactBindingServiceMockRecorder) RecordBinding(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordBinding", reflect.TypeOf((*MockContactBindingService)(nil).RecordBinding), arg0)
+}
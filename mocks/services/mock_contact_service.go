@@ -6,6 +6,7 @@ package mock_services
 
 import (
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	models "github.com/weni/whatsapp-router/models"
@@ -34,6 +35,20 @@ func (m *MockContactService) EXPECT() *MockContactServiceMockRecorder {
 	return m.recorder
 }
 
+// ClearBindingExpired mocks base method.
+func (m *MockContactService) ClearBindingExpired(arg0 *models.Contact) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClearBindingExpired", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ClearBindingExpired indicates an expected call of ClearBindingExpired.
+func (mr *MockContactServiceMockRecorder) ClearBindingExpired(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClearBindingExpired", reflect.TypeOf((*MockContactService)(nil).ClearBindingExpired), arg0)
+}
+
 // CreateContact mocks base method.
 func (m *MockContactService) CreateContact(arg0 *models.Contact) (*models.Contact, error) {
 	m.ctrl.T.Helper()
@@ -49,6 +64,21 @@ func (mr *MockContactServiceMockRecorder) CreateContact(arg0 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateContact", reflect.TypeOf((*MockContactService)(nil).CreateContact), arg0)
 }
 
+// ExpireBindings mocks base method.
+func (m *MockContactService) ExpireBindings(arg0 *models.Channel, arg1 time.Time) ([]*models.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpireBindings", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpireBindings indicates an expected call of ExpireBindings.
+func (mr *MockContactServiceMockRecorder) ExpireBindings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpireBindings", reflect.TypeOf((*MockContactService)(nil).ExpireBindings), arg0, arg1)
+}
+
 // FindContact mocks base method.
 func (m *MockContactService) FindContact(arg0 *models.Contact) (*models.Contact, error) {
 	m.ctrl.T.Helper()
@@ -64,6 +94,50 @@ func (mr *MockContactServiceMockRecorder) FindContact(arg0 interface{}) *gomock.
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindContact", reflect.TypeOf((*MockContactService)(nil).FindContact), arg0)
 }
 
+// ListContacts mocks base method.
+func (m *MockContactService) ListContacts(filter *models.Contact, page, limit int64) ([]*models.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListContacts", filter, page, limit)
+	ret0, _ := ret[0].([]*models.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListContacts indicates an expected call of ListContacts.
+func (mr *MockContactServiceMockRecorder) ListContacts(filter, page, limit interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListContacts", reflect.TypeOf((*MockContactService)(nil).ListContacts), filter, page, limit)
+}
+
+// RecordInbound mocks base method.
+func (m *MockContactService) RecordInbound(arg0 *models.Contact, arg1 time.Time) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RecordInbound", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RecordInbound indicates an expected call of RecordInbound.
+func (mr *MockContactServiceMockRecorder) RecordInbound(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RecordInbound", reflect.TypeOf((*MockContactService)(nil).RecordInbound), arg0, arg1)
+}
+
+// UnbindContact mocks base method.
+func (m *MockContactService) UnbindContact(arg0 *models.Contact) (*models.Contact, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnbindContact", arg0)
+	ret0, _ := ret[0].(*models.Contact)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UnbindContact indicates an expected call of UnbindContact.
+func (mr *MockContactServiceMockRecorder) UnbindContact(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnbindContact", reflect.TypeOf((*MockContactService)(nil).UnbindContact), arg0)
+}
+
 // UpdateContact mocks base method.
 func (m *MockContactService) UpdateContact(arg0 *models.Contact) (*models.Contact, error) {
 	m.ctrl.T.Helper()
@@ -8,6 +8,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	models "github.com/weni/whatsapp-router/models"
 )
 
 // MockCourierService is a mock of CourierService interface.
@@ -33,6 +34,21 @@ func (m *MockCourierService) EXPECT() *MockCourierServiceMockRecorder {
 	return m.recorder
 }
 
+// ListDeadLetters mocks base method.
+func (m *MockCourierService) ListDeadLetters(arg0, arg1 int64) ([]*models.Forward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListDeadLetters", arg0, arg1)
+	ret0, _ := ret[0].([]*models.Forward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListDeadLetters indicates an expected call of ListDeadLetters.
+func (mr *MockCourierServiceMockRecorder) ListDeadLetters(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListDeadLetters", reflect.TypeOf((*MockCourierService)(nil).ListDeadLetters), arg0, arg1)
+}
+
 // RedirectMessage mocks base method.
 func (m *MockCourierService) RedirectMessage(arg0, arg1 string) (int, error) {
 	m.ctrl.T.Helper()
@@ -47,3 +63,30 @@ func (mr *MockCourierServiceMockRecorder) RedirectMessage(arg0, arg1 interface{}
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RedirectMessage", reflect.TypeOf((*MockCourierService)(nil).RedirectMessage), arg0, arg1)
 }
+
+// ReplayDeadLetter mocks base method.
+func (m *MockCourierService) ReplayDeadLetter(arg0 string) (*models.Forward, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplayDeadLetter", arg0)
+	ret0, _ := ret[0].(*models.Forward)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplayDeadLetter indicates an expected call of ReplayDeadLetter.
+func (mr *MockCourierServiceMockRecorder) ReplayDeadLetter(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplayDeadLetter", reflect.TypeOf((*MockCourierService)(nil).ReplayDeadLetter), arg0)
+}
+
+// RetryPendingMessages mocks base method.
+func (m *MockCourierService) RetryPendingMessages() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RetryPendingMessages")
+}
+
+// RetryPendingMessages indicates an expected call of RetryPendingMessages.
+func (mr *MockCourierServiceMockRecorder) RetryPendingMessages() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RetryPendingMessages", reflect.TypeOf((*MockCourierService)(nil).RetryPendingMessages))
+}
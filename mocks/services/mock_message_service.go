@@ -0,0 +1,94 @@
+// Code generated by MockGen. DO NOT EDIT.
+// Source: ./services/message_service.go
+
+// Package mock_services is a generated GoMock package.
+package mock_services
+
+import (
+	reflect "reflect"
+
+	gomock "github.com/golang/mock/gomock"
+	models "github.com/weni/whatsapp-router/models"
+)
+
+// MockMessageService is a mock of MessageService interface.
+type MockMessageService struct {
+	ctrl     *gomock.Controller
+	recorder *MockMessageServiceMockRecorder
+}
+
+// MockMessageServiceMockRecorder is the mock recorder for MockMessageService.
+type MockMessageServiceMockRecorder struct {
+	mock *MockMessageService
+}
+
+// NewMockMessageService creates a new mock instance.
+func NewMockMessageService(ctrl *gomock.Controller) *MockMessageService {
+	mock := &MockMessageService{ctrl: ctrl}
+	mock.recorder = &MockMessageServiceMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockMessageService) EXPECT() *MockMessageServiceMockRecorder {
+	return m.recorder
+}
+
+// CreateMessage mocks base method.
+func (m *MockMessageService) CreateMessage(arg0 *models.Message) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateMessage", arg0)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateMessage indicates an expected call of CreateMessage.
+func (mr *MockMessageServiceMockRecorder) CreateMessage(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateMessage", reflect.TypeOf((*MockMessageService)(nil).CreateMessage), arg0)
+}
+
+// FindMessageByMessageID mocks base method.
+func (m *MockMessageService) FindMessageByMessageID(arg0 string) (*models.Message, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindMessageByMessageID", arg0)
+	ret0, _ := ret[0].(*models.Message)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindMessageByMessageID indicates an expected call of FindMessageByMessageID.
+func (mr *MockMessageServiceMockRecorder) FindMessageByMessageID(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindMessageByMessageID", reflect.TypeOf((*MockMessageService)(nil).FindMessageByMessageID), arg0)
+}
+
+// MarkAsProcessed mocks base method.
+func (m *MockMessageService) MarkAsProcessed(arg0 string) (bool, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkAsProcessed", arg0)
+	ret0, _ := ret[0].(bool)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkAsProcessed indicates an expected call of MarkAsProcessed.
+func (mr *MockMessageServiceMockRecorder) MarkAsProcessed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkAsProcessed", reflect.TypeOf((*MockMessageService)(nil).MarkAsProcessed), arg0)
+}
+
+// UnmarkAsProcessed mocks base method.
+func (m *MockMessageService) UnmarkAsProcessed(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UnmarkAsProcessed", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UnmarkAsProcessed indicates an expected call of UnmarkAsProcessed.
+func (mr *MockMessageServiceMockRecorder) UnmarkAsProcessed(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UnmarkAsProcessed", reflect.TypeOf((*MockMessageService)(nil).UnmarkAsProcessed), arg0)
+}
@@ -10,6 +10,7 @@ import (
 	reflect "reflect"
 
 	gomock "github.com/golang/mock/gomock"
+	models "github.com/weni/whatsapp-router/models"
 )
 
 // MockWhatsappService is a mock of WhatsappService interface.
@@ -35,6 +36,20 @@ func (m *MockWhatsappService) EXPECT() *MockWhatsappServiceMockRecorder {
 	return m.recorder
 }
 
+// Account mocks base method.
+func (m *MockWhatsappService) Account() *models.WhatsappAccount {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Account")
+	ret0, _ := ret[0].(*models.WhatsappAccount)
+	return ret0
+}
+
+// Account indicates an expected call of Account.
+func (mr *MockWhatsappServiceMockRecorder) Account() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Account", reflect.TypeOf((*MockWhatsappService)(nil).Account))
+}
+
 // GetMedia mocks base method.
 func (m *MockWhatsappService) GetMedia(arg0 http.Header, arg1 string) (*http.Response, error) {
 	m.ctrl.T.Helper()
@@ -50,6 +65,21 @@ func (mr *MockWhatsappServiceMockRecorder) GetMedia(arg0, arg1 interface{}) *gom
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetMedia", reflect.TypeOf((*MockWhatsappService)(nil).GetMedia), arg0, arg1)
 }
 
+// GetTemplates mocks base method.
+func (m *MockWhatsappService) GetTemplates() (*http.Response, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTemplates")
+	ret0, _ := ret[0].(*http.Response)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTemplates indicates an expected call of GetTemplates.
+func (mr *MockWhatsappServiceMockRecorder) GetTemplates() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTemplates", reflect.TypeOf((*MockWhatsappService)(nil).GetTemplates))
+}
+
 // Health mocks base method.
 func (m *MockWhatsappService) Health() (*http.Response, error) {
 	m.ctrl.T.Helper()
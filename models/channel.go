@@ -1,10 +1,44 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Channel struct {
-	ID    primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	UUID  string             `json:"uuid,omitempty"`
-	Name  string             `json:"name,omitempty"`
-	Token string             `json:"token,omitempty"`
+	ID              primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	UUID            string             `json:"uuid,omitempty"`
+	Name            string             `json:"name,omitempty"`
+	Token           string             `json:"token,omitempty"`
+	WelcomeMessage  string             `json:"welcome_message,omitempty" bson:"welcome_message,omitempty"`
+	WelcomeMessages map[string]string  `json:"welcome_messages,omitempty" bson:"welcome_messages,omitempty"`
+	Account         string             `json:"account,omitempty" bson:"account,omitempty"`
+	// Credential is the bearer token courier authenticates with to send
+	// messages from the channel. It is only returned when generated.
+	Credential string `json:"-" bson:"credential,omitempty"`
+	// BindingTTL and InactivityTimeout, in seconds, expire the binding of
+	// contacts to the channel that long after they were bound or after their
+	// last message. Zero never expires them.
+	BindingTTL        int64 `json:"binding_ttl,omitempty" bson:"binding_ttl,omitempty"`
+	InactivityTimeout int64 `json:"inactivity_timeout,omitempty" bson:"inactivity_timeout,omitempty"`
+}
+
+// ExpiresBindings reports whether the binding of contacts to the channel
+// expires.
+func (c *Channel) ExpiresBindings() bool {
+	return c.BindingTTL > 0 || c.InactivityTimeout > 0
+}
+
+// BindingExpiration returns the times before which contacts bound to the
+// channel, or whose last message was sent, have their binding expired at the
+// given time. The times are zero when the expiration is disabled.
+func (c *Channel) BindingExpiration(now time.Time) (boundBefore time.Time, inactiveBefore time.Time) {
+	if c.BindingTTL > 0 {
+		boundBefore = now.Add(-time.Duration(c.BindingTTL) * time.Second)
+	}
+	if c.InactivityTimeout > 0 {
+		inactiveBefore = now.Add(-time.Duration(c.InactivityTimeout) * time.Second)
+	}
+	return boundBefore, inactiveBefore
 }
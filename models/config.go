@@ -1,8 +1,13 @@
 package models
 
-import "go.mongodb.org/mongo-driver/bson/primitive"
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
 
 type Config struct {
-	ID    primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	Token string             `json:"token" bson:"token"`
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Token     string             `json:"token" bson:"token"`
+	ExpiresAt time.Time          `json:"expires_at,omitempty" bson:"expires_at,omitempty"`
 }
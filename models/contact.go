@@ -1,12 +1,40 @@
 package models
 
 import (
+	"time"
+
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// ServiceWindow is how long after the last message of a contact free-form
+// messages can be sent to it, later whatsapp only delivers templates.
+const ServiceWindow = 24 * time.Hour
+
 type Contact struct {
-	ID      primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
-	URN     string             `json:"urn,omitempty" bson:"urn,omitempty"`
-	Name    string             `json:"name,omitempty" bson:"name,omitempty"`
-	Channel primitive.ObjectID `json:"channel,omitempty" bson:"channel,omitempty"`
+	ID            primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	URN           string             `json:"urn,omitempty" bson:"urn,omitempty"`
+	Name          string             `json:"name,omitempty" bson:"name,omitempty"`
+	Channel       primitive.ObjectID `json:"channel,omitempty" bson:"channel,omitempty"`
+	Account       string             `json:"account,omitempty" bson:"account,omitempty"`
+	LastInboundAt time.Time          `json:"last_inbound_at,omitempty" bson:"last_inbound_at,omitempty"`
+	BoundAt       time.Time          `json:"bound_at,omitempty" bson:"bound_at,omitempty"`
+	// BindingExpired is set when the binding of the contact expires, until
+	// the contact is told so on its next message or binds again.
+	BindingExpired bool `json:"binding_expired,omitempty" bson:"binding_expired,omitempty"`
+}
+
+// ServiceWindowExpiresAt returns when the customer service window of the
+// contact closes, or the zero time if no message of the contact was recorded.
+func (c *Contact) ServiceWindowExpiresAt() time.Time {
+	if c.LastInboundAt.IsZero() {
+		return time.Time{}
+	}
+	return c.LastInboundAt.Add(ServiceWindow)
+}
+
+// InServiceWindow reports whether free-form messages can be sent to the
+// contact at the given time. Contacts bound before their messages were
+// recorded are assumed to be in the window.
+func (c *Contact) InServiceWindow(now time.Time) bool {
+	return c.LastInboundAt.IsZero() || now.Before(c.ServiceWindowExpiresAt())
 }
@@ -0,0 +1,39 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Events of the binding history of contacts.
+const (
+	BindingBind   = "bind"
+	BindingUnbind = "unbind"
+)
+
+// Reasons of the changes of the channel a contact is bound to.
+const (
+	BindingReasonToken         = "token"
+	BindingReasonStop          = "stop"
+	BindingReasonList          = "list"
+	BindingReasonAPI           = "api"
+	BindingReasonChannelDelete = "channel_delete"
+	BindingReasonExpired       = "expired"
+)
+
+// ContactBinding records a change of the channel a contact is bound to. Bind
+// events have the new Channel and unbind events only the PreviousChannel.
+// MessageID is the id of the whatsapp message that triggered the change, if
+// any.
+type ContactBinding struct {
+	ID              primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	URN             string             `json:"urn" bson:"urn"`
+	Account         string             `json:"account,omitempty" bson:"account,omitempty"`
+	Event           string             `json:"event" bson:"event"`
+	Channel         primitive.ObjectID `json:"channel,omitempty" bson:"channel,omitempty"`
+	PreviousChannel primitive.ObjectID `json:"previous_channel,omitempty" bson:"previous_channel,omitempty"`
+	MessageID       string             `json:"message_id,omitempty" bson:"message_id,omitempty"`
+	Reason          string             `json:"reason" bson:"reason"`
+	CreatedAt       time.Time          `json:"created_at" bson:"created_at"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Forward is a webhook payload pending redirection to the courier of a channel.
+type Forward struct {
+	ID          primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	ChannelUUID string             `json:"channel_uuid" bson:"channel_uuid"`
+	Payload     string             `json:"payload" bson:"payload"`
+	Attempts    int                `json:"attempts" bson:"attempts"`
+	LastError   string             `json:"last_error,omitempty" bson:"last_error,omitempty"`
+	NextAttempt time.Time          `json:"next_attempt" bson:"next_attempt"`
+	CreatedAt   time.Time          `json:"created_at" bson:"created_at"`
+	UpdatedAt   time.Time          `json:"updated_at" bson:"updated_at"`
+}
@@ -0,0 +1,15 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+type Message struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	MessageID string             `json:"message_id,omitempty" bson:"message_id,omitempty"`
+	URN       string             `json:"urn,omitempty" bson:"urn,omitempty"`
+	Channel   primitive.ObjectID `json:"channel,omitempty" bson:"channel,omitempty"`
+	CreatedAt time.Time          `json:"created_at,omitempty" bson:"created_at,omitempty"`
+}
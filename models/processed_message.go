@@ -0,0 +1,9 @@
+package models
+
+import "time"
+
+// ProcessedMessage registers an incoming whatsapp message id already handled.
+type ProcessedMessage struct {
+	ID        string    `json:"id" bson:"_id"`
+	CreatedAt time.Time `json:"created_at" bson:"created_at"`
+}
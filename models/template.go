@@ -0,0 +1,31 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// TemplateApproved is the status of the templates whatsapp delivers, templates
+// registered by hand have no status.
+const TemplateApproved = "APPROVED"
+
+// Template is a message template (HSM) of a whatsapp account, identified by
+// its name and language, whose body has ParameterCount parameters.
+type Template struct {
+	ID             primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Account        string             `json:"account,omitempty" bson:"account,omitempty"`
+	Name           string             `json:"name" bson:"name"`
+	Language       string             `json:"language" bson:"language"`
+	Namespace      string             `json:"namespace,omitempty" bson:"namespace,omitempty"`
+	Category       string             `json:"category,omitempty" bson:"category,omitempty"`
+	Status         string             `json:"status,omitempty" bson:"status,omitempty"`
+	ParameterCount int                `json:"parameter_count" bson:"parameter_count"`
+	UpdatedAt      time.Time          `json:"updated_at" bson:"updated_at"`
+}
+
+// IsSendable reports whether the template can be sent, only approved
+// templates being delivered by whatsapp.
+func (t *Template) IsSendable() bool {
+	return t.Status == "" || t.Status == TemplateApproved
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// Providers of the whatsapp api of an account, accounts without a provider
+// use the on-premises business api.
+const (
+	ProviderOnPremise = "on_premise"
+	ProviderCloud     = "cloud"
+)
+
+// WhatsappAccount is a WhatsApp Business API the router sends messages to and
+// receives webhooks from, at /wr/receive/{name}.
+type WhatsappAccount struct {
+	ID        primitive.ObjectID `json:"id,omitempty" bson:"_id,omitempty"`
+	Name      string             `json:"name" bson:"name"`
+	Provider  string             `json:"provider,omitempty" bson:"provider,omitempty"`
+	BaseURL   string             `json:"base_url" bson:"base_url"`
+	Username  string             `json:"username,omitempty" bson:"username,omitempty"`
+	Password  string             `json:"password,omitempty" bson:"password,omitempty"`
+	Token     string             `json:"-" bson:"token,omitempty"`
+	ExpiresAt time.Time          `json:"-" bson:"expires_at,omitempty"`
+
+	// cloud api accounts send messages from the phone number id with a
+	// permanent access token, and subscribe webhooks with the verify token.
+	PhoneNumberID string `json:"phone_number_id,omitempty" bson:"phone_number_id,omitempty"`
+	AccessToken   string `json:"access_token,omitempty" bson:"access_token,omitempty"`
+	VerifyToken   string `json:"verify_token,omitempty" bson:"verify_token,omitempty"`
+
+	// webhooks are only accepted when signed with the webhook secret, in the
+	// X-Hub-Signature-256 header, and sent from the allowed ips or networks,
+	// if they are set.
+	WebhookSecret     string   `json:"webhook_secret,omitempty" bson:"webhook_secret,omitempty"`
+	WebhookAllowedIPs []string `json:"webhook_allowed_ips,omitempty" bson:"webhook_allowed_ips,omitempty"`
+}
+
+// IsCloud reports whether the account uses the whatsapp cloud api.
+func (a *WhatsappAccount) IsCloud() bool {
+	return a.Provider == ProviderCloud
+}
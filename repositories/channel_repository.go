@@ -9,6 +9,7 @@ import (
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const CHANNEL_COLLECTION = "channel"
@@ -18,6 +19,11 @@ type ChannelRepository interface {
 	FindOne(*models.Channel) (*models.Channel, error)
 	FindById(string) (*models.Channel, error)
 	FindByToken(string) (*models.Channel, error)
+	FindByCredential(string) (*models.Channel, error)
+	FindAll(int64, int64) ([]*models.Channel, error)
+	Update(*models.Channel) error
+	Delete(string) error
+	CreateIndexes() error
 }
 
 type ChannelRepositoryDb struct {
@@ -26,6 +32,9 @@ type ChannelRepositoryDb struct {
 
 func (c ChannelRepositoryDb) Insert(channel *models.Channel) error {
 	result, err := c.DB.Collection(CHANNEL_COLLECTION).InsertOne(context.TODO(), channel)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
 	if err != nil {
 		return errors.New("unexpected database error: " + err.Error())
 	}
@@ -41,6 +50,9 @@ func (c ChannelRepositoryDb) FindOne(channel *models.Channel) (*models.Channel,
 		"uuid": channel.UUID,
 	}
 	if err := c.DB.Collection(CHANNEL_COLLECTION).FindOne(context.TODO(), qry).Decode(&ch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
 		return nil, errors.New("unexpected database error: " + err.Error())
 	}
 	return &ch, nil
@@ -69,6 +81,91 @@ func (c ChannelRepositoryDb) FindByToken(token string) (*models.Channel, error)
 	return &ch, nil
 }
 
+func (c ChannelRepositoryDb) FindByCredential(credential string) (*models.Channel, error) {
+	var ch models.Channel
+	qry := bson.M{
+		"credential": credential,
+	}
+	if err := c.DB.Collection(CHANNEL_COLLECTION).FindOne(context.TODO(), qry).Decode(&ch); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return &ch, nil
+}
+
+func (c ChannelRepositoryDb) FindAll(skip int64, limit int64) ([]*models.Channel, error) {
+	opts := options.Find().
+		SetSort(bson.M{"_id": 1}).
+		SetSkip(skip).
+		SetLimit(limit)
+	cursor, err := c.DB.Collection(CHANNEL_COLLECTION).Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	channels := []*models.Channel{}
+	if err := cursor.All(context.TODO(), &channels); err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return channels, nil
+}
+
+func (c ChannelRepositoryDb) Update(channel *models.Channel) error {
+	qry := bson.M{
+		"_id": channel.ID,
+	}
+	result, err := c.DB.Collection(CHANNEL_COLLECTION).ReplaceOne(context.TODO(), qry, channel)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (c ChannelRepositoryDb) Delete(id string) error {
+	objId, _ := primitive.ObjectIDFromHex(id)
+	result, err := c.DB.Collection(CHANNEL_COLLECTION).DeleteOne(context.TODO(), bson.M{"_id": objId})
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateIndexes creates the unique indexes of channel uuid, token and
+// credential, channels created before credentials are skipped by the latter.
+func (c ChannelRepositoryDb) CreateIndexes() error {
+	_, err := c.DB.Collection(CHANNEL_COLLECTION).Indexes().CreateMany(
+		context.TODO(),
+		[]mongo.IndexModel{
+			{
+				Keys:    bson.M{"uuid": 1},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.M{"token": 1},
+				Options: options.Index().SetUnique(true),
+			},
+			{
+				Keys:    bson.M{"credential": 1},
+				Options: options.Index().SetUnique(true).SetSparse(true),
+			},
+		},
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
 func NewChannelRepositoryDb(dbClient *mongo.Database) ChannelRepositoryDb {
 	return ChannelRepositoryDb{dbClient}
 }
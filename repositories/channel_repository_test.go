@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/weni/whatsapp-router/models"
 	"github.com/weni/whatsapp-router/storage"
 )
@@ -72,3 +73,96 @@ func TestFindOneChannel(t *testing.T) {
 		})
 	}
 }
+
+func TestChannelManagement(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	channelRepository := NewChannelRepositoryDb(mongodb)
+
+	ch := &models.Channel{
+		UUID:  "5b4f3f4e-63a3-4a3b-8b0e-2bd2f4a6b2c1",
+		Name:  "management",
+		Token: "weni-demo-management",
+	}
+	err := channelRepository.Insert(ch)
+	assert.Nil(t, err)
+
+	channels, err := channelRepository.FindAll(0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, channels, 1)
+
+	ch.Name = "renamed"
+	err = channelRepository.Update(ch)
+	assert.Nil(t, err)
+	found, err := channelRepository.FindOne(ch)
+	assert.Nil(t, err)
+	assert.Equal(t, "renamed", found.Name)
+
+	err = channelRepository.Delete(ch.ID.Hex())
+	assert.Nil(t, err)
+	_, err = channelRepository.FindOne(ch)
+	assert.Equal(t, ErrNotFound, err)
+	err = channelRepository.Delete(ch.ID.Hex())
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestChannelUniqueIndexes(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	channelRepository := NewChannelRepositoryDb(mongodb)
+	err := channelRepository.CreateIndexes()
+	assert.Nil(t, err)
+
+	err = channelRepository.Insert(&models.Channel{
+		UUID:  "0c3f6f1e-3b7e-4f8e-9a55-7f2f0b0b6a10",
+		Name:  "unique",
+		Token: "weni-demo-unique",
+	})
+	assert.Nil(t, err)
+
+	err = channelRepository.Insert(&models.Channel{
+		UUID:  "0c3f6f1e-3b7e-4f8e-9a55-7f2f0b0b6a10",
+		Name:  "same uuid",
+		Token: "weni-demo-other",
+	})
+	assert.Equal(t, ErrDuplicate, err)
+
+	err = channelRepository.Insert(&models.Channel{
+		UUID:  "a8d2f6c4-1f0e-4b55-b7a9-8d7e2c9f3e21",
+		Name:  "same token",
+		Token: "weni-demo-unique",
+	})
+	assert.Equal(t, ErrDuplicate, err)
+}
+
+func TestFindChannelByCredential(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	channelRepository := NewChannelRepositoryDb(mongodb)
+	err := channelRepository.CreateIndexes()
+	assert.Nil(t, err)
+
+	// channels created before credentials have none
+	err = channelRepository.Insert(&models.Channel{UUID: "3e0b8c1a-6d0f-4a6e-9c3b-1f2e3d4c5b6a", Token: "weni-demo-legacy0001"})
+	assert.Nil(t, err)
+	err = channelRepository.Insert(&models.Channel{UUID: "7a6b5c4d-3e2f-4a1b-8c9d-0e1f2a3b4c5d", Token: "weni-demo-legacy0002"})
+	assert.Nil(t, err)
+
+	ch := &models.Channel{
+		UUID:       "c2b7e1f0-9d8a-4c3b-a2e1-f0e9d8c7b6a5",
+		Token:      "weni-demo-credential",
+		Credential: "Zm9vYmFyYmF6",
+	}
+	err = channelRepository.Insert(ch)
+	assert.Nil(t, err)
+
+	found, err := channelRepository.FindByCredential(ch.Credential)
+	assert.Nil(t, err)
+	assert.Equal(t, ch.UUID, found.UUID)
+
+	_, err = channelRepository.FindByCredential("unknown")
+	assert.Equal(t, ErrNotFound, err)
+}
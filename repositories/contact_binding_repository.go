@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const CONTACT_BINDING_COLLECTION = "contact_bindings"
+
+// ContactBindingRepository stores the append-only history of the channels
+// contacts were bound to.
+type ContactBindingRepository interface {
+	Insert(binding *models.ContactBinding) error
+	FindByContact(urn string, account string, skip int64, limit int64) ([]*models.ContactBinding, error)
+	CreateIndexes() error
+}
+
+type ContactBindingRepositoryDb struct {
+	DB *mongo.Database
+}
+
+func (c ContactBindingRepositoryDb) Insert(binding *models.ContactBinding) error {
+	result, err := c.DB.Collection(CONTACT_BINDING_COLLECTION).InsertOne(context.TODO(), binding)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		binding.ID = id
+	}
+	return nil
+}
+
+// FindByContact returns the binding history of the contact of the account,
+// most recent first.
+func (c ContactBindingRepositoryDb) FindByContact(urn string, account string, skip int64, limit int64) ([]*models.ContactBinding, error) {
+	opts := options.Find().
+		SetSort(bson.D{{Key: "created_at", Value: -1}, {Key: "_id", Value: -1}}).
+		SetSkip(skip).
+		SetLimit(limit)
+	cursor, err := c.DB.Collection(CONTACT_BINDING_COLLECTION).Find(
+		context.TODO(),
+		bson.M{"urn": urn, "account": accountFilter(account)},
+		opts,
+	)
+	if err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	bindings := []*models.ContactBinding{}
+	if err := cursor.All(context.TODO(), &bindings); err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return bindings, nil
+}
+
+// CreateIndexes creates the index of the history of each contact.
+func (c ContactBindingRepositoryDb) CreateIndexes() error {
+	_, err := c.DB.Collection(CONTACT_BINDING_COLLECTION).Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "account", Value: 1},
+				{Key: "urn", Value: 1},
+				{Key: "created_at", Value: -1},
+			},
+		},
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func NewContactBindingRepositoryDb(dbClient *mongo.Database) ContactBindingRepositoryDb {
+	return ContactBindingRepositoryDb{dbClient}
+}
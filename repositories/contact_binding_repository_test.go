@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/storage"
+)
+
+func TestContactBindingRepository(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	bindingRepository := NewContactBindingRepositoryDb(mongodb)
+	err := bindingRepository.CreateIndexes()
+	assert.Nil(t, err)
+
+	now := time.Now().UTC().Truncate(time.Millisecond)
+	bind := &models.ContactBinding{
+		URN:       "5582988887777",
+		Event:     models.BindingBind,
+		Channel:   dummyChannel.ID,
+		MessageID: "ABGGFlA5FpafAgo6EhvdfU3dKgo5",
+		Reason:    models.BindingReasonToken,
+		CreatedAt: now,
+	}
+	err = bindingRepository.Insert(bind)
+	assert.Nil(t, err)
+	assert.False(t, bind.ID.IsZero())
+	err = bindingRepository.Insert(&models.ContactBinding{
+		URN:             "5582988887777",
+		Event:           models.BindingUnbind,
+		PreviousChannel: dummyChannel.ID,
+		Reason:          models.BindingReasonStop,
+		CreatedAt:       now.Add(time.Minute),
+	})
+	assert.Nil(t, err)
+	err = bindingRepository.Insert(&models.ContactBinding{
+		URN:       "5582988887777",
+		Account:   "demo-2",
+		Event:     models.BindingBind,
+		Channel:   dummyChannel2.ID,
+		Reason:    models.BindingReasonToken,
+		CreatedAt: now,
+	})
+	assert.Nil(t, err)
+
+	bindings, err := bindingRepository.FindByContact("5582988887777", "", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, bindings, 2)
+	assert.Equal(t, models.BindingUnbind, bindings[0].Event)
+	assert.Equal(t, dummyChannel.ID, bindings[0].PreviousChannel)
+	assert.Equal(t, bind.ID, bindings[1].ID)
+	assert.Equal(t, "ABGGFlA5FpafAgo6EhvdfU3dKgo5", bindings[1].MessageID)
+
+	bindings, err = bindingRepository.FindByContact("5582988887777", "", 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, bindings, 1)
+
+	bindings, err = bindingRepository.FindByContact("5582988887777", "demo-2", 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, bindings, 1)
+	assert.Equal(t, dummyChannel2.ID, bindings[0].Channel)
+}
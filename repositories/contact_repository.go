@@ -3,11 +3,15 @@ package repositories
 import (
 	"context"
 	"errors"
+	"fmt"
+	"strings"
+	"time"
 
 	"github.com/weni/whatsapp-router/models"
 	"go.mongodb.org/mongo-driver/bson"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
 )
 
 const CONTACT_COLLECTION = "contact"
@@ -15,7 +19,14 @@ const CONTACT_COLLECTION = "contact"
 type ContactRepository interface {
 	Insert(contact *models.Contact) (*models.Contact, error)
 	FindOne(contact *models.Contact) (*models.Contact, error)
+	FindAll(filter *models.Contact, skip int64, limit int64) ([]*models.Contact, error)
 	Update(contact *models.Contact) (*models.Contact, error)
+	ReassignChannel(from primitive.ObjectID, to primitive.ObjectID) (int64, error)
+	UnsetChannel(contact *models.Contact) error
+	SetLastInbound(contact *models.Contact, at time.Time) error
+	ExpireBindings(channel primitive.ObjectID, boundBefore time.Time, inactiveBefore time.Time) ([]*models.Contact, error)
+	ClearBindingExpired(contact *models.Contact) error
+	CreateIndexes() error
 }
 
 type ContactRepositoryDb struct {
@@ -24,6 +35,9 @@ type ContactRepositoryDb struct {
 
 func (c ContactRepositoryDb) Insert(contact *models.Contact) (*models.Contact, error) {
 	result, err := c.DB.Collection(CONTACT_COLLECTION).InsertOne(context.TODO(), contact)
+	if mongo.IsDuplicateKeyError(err) {
+		return nil, ErrDuplicate
+	}
 	if err != nil {
 		return nil, errors.New("unexpected database error - " + err.Error())
 	}
@@ -38,17 +52,46 @@ func (c ContactRepositoryDb) Insert(contact *models.Contact) (*models.Contact, e
 func (c ContactRepositoryDb) FindOne(contact *models.Contact) (*models.Contact, error) {
 	var cont models.Contact
 	qry := bson.M{
-		"urn": contact.URN,
+		"urn":     contact.URN,
+		"account": accountFilter(contact.Account),
 	}
 	if err := c.DB.Collection(CONTACT_COLLECTION).FindOne(context.TODO(), qry).Decode(&cont); err != nil {
-		return nil, errors.New("contact not found")
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New("unexpected database error - " + err.Error())
 	}
 	return &cont, nil
 }
 
+// FindAll returns the contacts of the account of the filter, bound to its
+// channel if it has one, sorted by urn.
+func (c ContactRepositoryDb) FindAll(filter *models.Contact, skip int64, limit int64) ([]*models.Contact, error) {
+	qry := bson.M{
+		"account": accountFilter(filter.Account),
+	}
+	if !filter.Channel.IsZero() {
+		qry["channel"] = filter.Channel
+	}
+	opts := options.Find().
+		SetSort(bson.M{"urn": 1}).
+		SetSkip(skip).
+		SetLimit(limit)
+	cursor, err := c.DB.Collection(CONTACT_COLLECTION).Find(context.TODO(), qry, opts)
+	if err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	contacts := []*models.Contact{}
+	if err := cursor.All(context.TODO(), &contacts); err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	return contacts, nil
+}
+
 func (c ContactRepositoryDb) Update(contact *models.Contact) (*models.Contact, error) {
 	q := bson.M{
-		"urn": contact.URN,
+		"urn":     contact.URN,
+		"account": accountFilter(contact.Account),
 	}
 	d, err := bson.Marshal(contact)
 	if err != nil {
@@ -59,13 +102,11 @@ func (c ContactRepositoryDb) Update(contact *models.Contact) (*models.Contact, e
 	if err != nil {
 		return nil, errors.New("internal server error: " + err.Error())
 	}
-	result, err := c.DB.Collection(CONTACT_COLLECTION).UpdateOne(
-		context.TODO(),
-		q,
-		bson.D{
-			{Key: "$set", Value: dc},
-		},
-	)
+	update := bson.D{{Key: "$set", Value: dc}}
+	if !contact.Channel.IsZero() {
+		update = append(update, bson.E{Key: "$unset", Value: bson.M{"binding_expired": ""}})
+	}
+	result, err := c.DB.Collection(CONTACT_COLLECTION).UpdateOne(context.TODO(), q, update)
 	if err != nil {
 		return nil, errors.New("unexpected database error - " + err.Error())
 	}
@@ -76,6 +117,217 @@ func (c ContactRepositoryDb) Update(contact *models.Contact) (*models.Contact, e
 	return contact, nil
 }
 
+// ReassignChannel binds every contact of the from channel to the to channel,
+// or unbinds them if to is a zero ObjectID, returning how many were changed.
+func (c ContactRepositoryDb) ReassignChannel(from primitive.ObjectID, to primitive.ObjectID) (int64, error) {
+	q := bson.M{
+		"channel": from,
+	}
+	update := bson.M{"$set": bson.M{"channel": to, "bound_at": time.Now().UTC()}}
+	if to.IsZero() {
+		update = bson.M{"$unset": bson.M{"channel": "", "bound_at": ""}}
+	}
+	result, err := c.DB.Collection(CONTACT_COLLECTION).UpdateMany(context.TODO(), q, update)
+	if err != nil {
+		return 0, errors.New("unexpected database error - " + err.Error())
+	}
+	return result.ModifiedCount, nil
+}
+
+// UnsetChannel unbinds the contact from its channel.
+func (c ContactRepositoryDb) UnsetChannel(contact *models.Contact) error {
+	q := bson.M{
+		"urn":     contact.URN,
+		"account": accountFilter(contact.Account),
+	}
+	update := bson.M{"$unset": bson.M{"channel": "", "bound_at": ""}}
+	result, err := c.DB.Collection(CONTACT_COLLECTION).UpdateOne(context.TODO(), q, update)
+	if err != nil {
+		return errors.New("unexpected database error - " + err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	contact.Channel = primitive.NilObjectID
+	contact.BoundAt = time.Time{}
+	return nil
+}
+
+// SetLastInbound records the time of the last message received from the
+// contact, keeping the latest one if messages are delivered out of order.
+func (c ContactRepositoryDb) SetLastInbound(contact *models.Contact, at time.Time) error {
+	q := bson.M{
+		"urn":     contact.URN,
+		"account": accountFilter(contact.Account),
+	}
+	update := bson.M{"$max": bson.M{"last_inbound_at": at}}
+	result, err := c.DB.Collection(CONTACT_COLLECTION).UpdateOne(context.TODO(), q, update)
+	if err != nil {
+		return errors.New("unexpected database error - " + err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// ExpireBindings unbinds the contacts of the channel bound before boundBefore,
+// or bound and without messages since inactiveBefore, marking their binding
+// as expired, and returns them. Zero times disable each expiration. Contacts
+// bound before binding times were recorded are considered bound now.
+func (c ContactRepositoryDb) ExpireBindings(channel primitive.ObjectID, boundBefore time.Time, inactiveBefore time.Time) ([]*models.Contact, error) {
+	var expired bson.A
+	if !boundBefore.IsZero() {
+		expired = append(expired, bson.M{"bound_at": bson.M{"$lt": boundBefore}})
+	}
+	if !inactiveBefore.IsZero() {
+		expired = append(expired, bson.M{
+			"bound_at": bson.M{"$lt": inactiveBefore},
+			"$or": bson.A{
+				bson.M{"last_inbound_at": bson.M{"$exists": false}},
+				bson.M{"last_inbound_at": bson.M{"$lt": inactiveBefore}},
+			},
+		})
+	}
+	contacts := []*models.Contact{}
+	if len(expired) == 0 {
+		return contacts, nil
+	}
+
+	collection := c.DB.Collection(CONTACT_COLLECTION)
+	_, err := collection.UpdateMany(
+		context.TODO(),
+		bson.M{"channel": channel, "bound_at": bson.M{"$exists": false}},
+		bson.M{"$set": bson.M{"bound_at": time.Now().UTC()}},
+	)
+	if err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	cursor, err := collection.Find(context.TODO(), bson.M{"channel": channel, "$or": expired})
+	if err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	var found []*models.Contact
+	if err := cursor.All(context.TODO(), &found); err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	for _, contact := range found {
+		// the contact may have been bound again since it was found
+		result, err := collection.UpdateOne(
+			context.TODO(),
+			bson.M{"_id": contact.ID, "channel": channel, "$or": expired},
+			bson.M{
+				"$unset": bson.M{"channel": "", "bound_at": ""},
+				"$set":   bson.M{"binding_expired": true},
+			},
+		)
+		if err != nil {
+			return contacts, errors.New("unexpected database error - " + err.Error())
+		}
+		if result.ModifiedCount == 0 {
+			continue
+		}
+		contact.Channel = primitive.NilObjectID
+		contact.BoundAt = time.Time{}
+		contact.BindingExpired = true
+		contacts = append(contacts, contact)
+	}
+	return contacts, nil
+}
+
+// ClearBindingExpired unmarks the expired binding of the contact.
+func (c ContactRepositoryDb) ClearBindingExpired(contact *models.Contact) error {
+	q := bson.M{
+		"urn":     contact.URN,
+		"account": accountFilter(contact.Account),
+	}
+	update := bson.M{"$unset": bson.M{"binding_expired": ""}}
+	if _, err := c.DB.Collection(CONTACT_COLLECTION).UpdateOne(context.TODO(), q, update); err != nil {
+		return errors.New("unexpected database error - " + err.Error())
+	}
+	contact.BindingExpired = false
+	return nil
+}
+
+// CreateIndexes creates the unique index of contact urn in each whatsapp
+// account, replacing the former unique index of urn once it is created. If
+// existing contacts have duplicated urns in an account, the former index is
+// kept and an ErrDuplicate error listing some of them is returned.
+func (c ContactRepositoryDb) CreateIndexes() error {
+	indexes := c.DB.Collection(CONTACT_COLLECTION).Indexes()
+	_, err := indexes.CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: "account", Value: 1}, {Key: "urn", Value: 1}},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if mongo.IsDuplicateKeyError(err) {
+		urns, err := c.findDuplicatedURNs(10)
+		if err != nil {
+			return err
+		}
+		return fmt.Errorf("%w: contacts with duplicated urns in an account: %s", ErrDuplicate, strings.Join(urns, ", "))
+	}
+	if err != nil {
+		return errors.New("unexpected database error - " + err.Error())
+	}
+	if _, err := indexes.DropOne(context.TODO(), "urn_1"); err != nil && !isIndexNotFound(err) {
+		return errors.New("unexpected database error - " + err.Error())
+	}
+	return nil
+}
+
+// findDuplicatedURNs returns up to limit urns shared by more than one contact
+// of the same account.
+func (c ContactRepositoryDb) findDuplicatedURNs(limit int64) ([]string, error) {
+	pipeline := mongo.Pipeline{
+		{{Key: "$group", Value: bson.M{
+			"_id":   bson.M{"account": bson.M{"$ifNull": bson.A{"$account", ""}}, "urn": "$urn"},
+			"count": bson.M{"$sum": 1},
+		}}},
+		{{Key: "$match", Value: bson.M{"count": bson.M{"$gt": 1}}}},
+		{{Key: "$sort", Value: bson.M{"_id.urn": 1}}},
+		{{Key: "$limit", Value: limit}},
+	}
+	cursor, err := c.DB.Collection(CONTACT_COLLECTION).Aggregate(context.TODO(), pipeline)
+	if err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	var groups []struct {
+		ID struct {
+			URN string `bson:"urn"`
+		} `bson:"_id"`
+	}
+	if err := cursor.All(context.TODO(), &groups); err != nil {
+		return nil, errors.New("unexpected database error - " + err.Error())
+	}
+	urns := make([]string, 0, len(groups))
+	for _, group := range groups {
+		urns = append(urns, group.ID.URN)
+	}
+	return urns, nil
+}
+
+// accountFilter matches the contacts of the whatsapp account, contacts of the
+// default account have no account.
+func accountFilter(account string) interface{} {
+	if account == "" {
+		return bson.M{"$in": bson.A{nil, ""}}
+	}
+	return account
+}
+
+// isIndexNotFound reports whether the error is due to a missing index or
+// collection.
+func isIndexNotFound(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == 27 || cmdErr.Code == 26 // IndexNotFound, NamespaceNotFound
+	}
+	return false
+}
+
 func NewContactRepositoryDb(dbClient *mongo.Database) ContactRepositoryDb {
 	return ContactRepositoryDb{dbClient}
 }
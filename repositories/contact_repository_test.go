@@ -1,10 +1,13 @@
 package repositories
 
 import (
+	"errors"
 	"fmt"
 	"reflect"
 	"testing"
+	"time"
 
+	"github.com/stretchr/testify/assert"
 	"github.com/weni/whatsapp-router/models"
 	"github.com/weni/whatsapp-router/storage"
 	"go.mongodb.org/mongo-driver/bson/primitive"
@@ -130,3 +133,192 @@ func TestUpdateContact(t *testing.T) {
 		})
 	}
 }
+
+func TestReassignContactsChannel(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+
+	_, err := contactRepository.Insert(&models.Contact{URN: "5582911112222", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+	_, err = contactRepository.Insert(&models.Contact{URN: "5582933334444", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+
+	reassigned, err := contactRepository.ReassignChannel(dummyChannel.ID, dummyChannel2.ID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), reassigned)
+
+	unbound, err := contactRepository.ReassignChannel(dummyChannel2.ID, primitive.NilObjectID)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(2), unbound)
+
+	c, err := contactRepository.FindOne(&models.Contact{URN: "5582911112222"})
+	assert.Nil(t, err)
+	assert.True(t, c.Channel.IsZero())
+}
+
+func TestUnsetContactChannel(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+
+	contact, err := contactRepository.Insert(&models.Contact{URN: "5582955556666", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+
+	err = contactRepository.UnsetChannel(contact)
+	assert.Nil(t, err)
+	assert.True(t, contact.Channel.IsZero())
+
+	c, err := contactRepository.FindOne(&models.Contact{URN: "5582955556666"})
+	assert.Nil(t, err)
+	assert.True(t, c.Channel.IsZero())
+
+	err = contactRepository.UnsetChannel(&models.Contact{URN: "5582900000000"})
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSetContactLastInbound(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+
+	contact, err := contactRepository.Insert(&models.Contact{URN: "5582944443333", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+
+	last := time.Date(2022, 5, 10, 12, 0, 0, 0, time.UTC)
+	assert.Nil(t, contactRepository.SetLastInbound(contact, last))
+	// messages delivered out of order do not move the last inbound back
+	assert.Nil(t, contactRepository.SetLastInbound(contact, last.Add(-time.Minute)))
+
+	c, err := contactRepository.FindOne(&models.Contact{URN: "5582944443333"})
+	assert.Nil(t, err)
+	assert.True(t, last.Equal(c.LastInboundAt))
+
+	err = contactRepository.SetLastInbound(&models.Contact{URN: "5582900000000"}, last)
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestExpireBindings(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+
+	now := time.Now().UTC()
+	for _, c := range []*models.Contact{
+		{URN: "5582922221111", Channel: dummyChannel.ID, BoundAt: now.Add(-48 * time.Hour), LastInboundAt: now},
+		{URN: "5582922222222", Channel: dummyChannel.ID, BoundAt: now.Add(-2 * time.Hour), LastInboundAt: now.Add(-2 * time.Hour)},
+		{URN: "5582922223333", Channel: dummyChannel.ID, BoundAt: now.Add(-2 * time.Hour), LastInboundAt: now},
+		{URN: "5582922224444", Channel: dummyChannel.ID},
+		{URN: "5582922225555", Channel: dummyChannel2.ID, BoundAt: now.Add(-48 * time.Hour)},
+	} {
+		_, err := contactRepository.Insert(c)
+		assert.Nil(t, err)
+	}
+
+	expired, err := contactRepository.ExpireBindings(dummyChannel.ID, now.Add(-24*time.Hour), now.Add(-time.Hour))
+	assert.Nil(t, err)
+	urns := []string{}
+	for _, c := range expired {
+		urns = append(urns, c.URN)
+	}
+	assert.ElementsMatch(t, []string{"5582922221111", "5582922222222"}, urns)
+
+	c, err := contactRepository.FindOne(&models.Contact{URN: "5582922221111"})
+	assert.Nil(t, err)
+	assert.True(t, c.Channel.IsZero())
+	assert.True(t, c.BindingExpired)
+	// contacts bound before binding times were recorded are considered bound now
+	c, err = contactRepository.FindOne(&models.Contact{URN: "5582922224444"})
+	assert.Nil(t, err)
+	assert.Equal(t, dummyChannel.ID, c.Channel)
+	assert.False(t, c.BoundAt.IsZero())
+
+	assert.Nil(t, contactRepository.ClearBindingExpired(&models.Contact{URN: "5582922221111"}))
+	c, err = contactRepository.FindOne(&models.Contact{URN: "5582922221111"})
+	assert.Nil(t, err)
+	assert.False(t, c.BindingExpired)
+
+	expired, err = contactRepository.ExpireBindings(dummyChannel.ID, time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Empty(t, expired)
+}
+
+func TestFindAllContacts(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+
+	for _, c := range []*models.Contact{
+		{URN: "5582911112222", Channel: dummyChannel.ID},
+		{URN: "5582911113333", Channel: dummyChannel.ID},
+		{URN: "5582911114444", Channel: dummyChannel2.ID},
+		{URN: "5582911115555", Channel: dummyChannel.ID, Account: "demo-2"},
+	} {
+		_, err := contactRepository.Insert(c)
+		assert.Nil(t, err)
+	}
+
+	contacts, err := contactRepository.FindAll(&models.Contact{}, 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, contacts, 3)
+
+	contacts, err = contactRepository.FindAll(&models.Contact{Channel: dummyChannel.ID}, 1, 10)
+	assert.Nil(t, err)
+	assert.Len(t, contacts, 1)
+	assert.Equal(t, "5582911113333", contacts[0].URN)
+
+	contacts, err = contactRepository.FindAll(&models.Contact{Account: "demo-2"}, 0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, contacts, 1)
+}
+
+func TestContactsByAccount(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+	err := contactRepository.CreateIndexes()
+	assert.Nil(t, err)
+
+	_, err = contactRepository.Insert(&models.Contact{URN: "5582977778888", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+	_, err = contactRepository.Insert(&models.Contact{URN: "5582977778888", Channel: dummyChannel2.ID, Account: "demo-2"})
+	assert.Nil(t, err)
+	_, err = contactRepository.Insert(&models.Contact{URN: "5582977778888", Account: "demo-2"})
+	assert.Equal(t, ErrDuplicate, err)
+
+	c, err := contactRepository.FindOne(&models.Contact{URN: "5582977778888"})
+	assert.Nil(t, err)
+	assert.Equal(t, dummyChannel.ID, c.Channel)
+
+	c, err = contactRepository.FindOne(&models.Contact{URN: "5582977778888", Account: "demo-2"})
+	assert.Nil(t, err)
+	assert.Equal(t, dummyChannel2.ID, c.Channel)
+
+	_, err = contactRepository.FindOne(&models.Contact{URN: "5582977778888", Account: "demo-3"})
+	assert.NotNil(t, err)
+}
+
+func TestCreateContactIndexesWithDuplicatedURNs(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	contactRepository := NewContactRepositoryDb(mongodb)
+
+	_, err := contactRepository.Insert(&models.Contact{URN: "5582977778888", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+	_, err = contactRepository.Insert(&models.Contact{URN: "5582977778888", Channel: dummyChannel2.ID})
+	assert.Nil(t, err)
+	_, err = contactRepository.Insert(&models.Contact{URN: "5582977779999", Channel: dummyChannel.ID})
+	assert.Nil(t, err)
+
+	err = contactRepository.CreateIndexes()
+	assert.True(t, errors.Is(err, ErrDuplicate))
+	assert.Contains(t, err.Error(), "5582977778888")
+	assert.NotContains(t, err.Error(), "5582977779999")
+}
@@ -0,0 +1,8 @@
+package repositories
+
+import "errors"
+
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrDuplicate = errors.New("duplicate key")
+)
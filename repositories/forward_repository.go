@@ -0,0 +1,136 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const FORWARD_COLLECTION = "forward"
+const FORWARD_DEAD_LETTER_COLLECTION = "forward_dead_letter"
+
+type ForwardRepository interface {
+	Insert(*models.Forward) error
+	AcquireNext(time.Time, time.Duration) (*models.Forward, error)
+	Update(*models.Forward) error
+	Delete(string) error
+	InsertDeadLetter(*models.Forward) error
+	FindDeadLetters(int64, int64) ([]*models.Forward, error)
+	FindDeadLetterById(string) (*models.Forward, error)
+	DeleteDeadLetter(string) error
+}
+
+type ForwardRepositoryDb struct {
+	DB *mongo.Database
+}
+
+func (f ForwardRepositoryDb) Insert(forward *models.Forward) error {
+	result, err := f.DB.Collection(FORWARD_COLLECTION).InsertOne(context.TODO(), forward)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		forward.ID = id
+	}
+	return nil
+}
+
+// AcquireNext returns the next forward due at now, postponing its next attempt
+// by lease so it is not picked up again while being processed. It returns nil
+// when there is no forward due.
+func (f ForwardRepositoryDb) AcquireNext(now time.Time, lease time.Duration) (*models.Forward, error) {
+	var fw models.Forward
+	qry := bson.M{
+		"next_attempt": bson.M{"$lte": now},
+	}
+	update := bson.M{
+		"$set": bson.M{"next_attempt": now.Add(lease)},
+	}
+	opts := options.FindOneAndUpdate().SetSort(bson.M{"next_attempt": 1})
+	err := f.DB.Collection(FORWARD_COLLECTION).FindOneAndUpdate(context.TODO(), qry, update, opts).Decode(&fw)
+	if err == mongo.ErrNoDocuments {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return &fw, nil
+}
+
+func (f ForwardRepositoryDb) Update(forward *models.Forward) error {
+	qry := bson.M{
+		"_id": forward.ID,
+	}
+	_, err := f.DB.Collection(FORWARD_COLLECTION).ReplaceOne(context.TODO(), qry, forward)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func (f ForwardRepositoryDb) Delete(id string) error {
+	objId, _ := primitive.ObjectIDFromHex(id)
+	_, err := f.DB.Collection(FORWARD_COLLECTION).DeleteOne(context.TODO(), bson.M{"_id": objId})
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func (f ForwardRepositoryDb) InsertDeadLetter(forward *models.Forward) error {
+	_, err := f.DB.Collection(FORWARD_DEAD_LETTER_COLLECTION).InsertOne(context.TODO(), forward)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func (f ForwardRepositoryDb) FindDeadLetters(skip int64, limit int64) ([]*models.Forward, error) {
+	opts := options.Find().
+		SetSort(bson.M{"updated_at": -1}).
+		SetSkip(skip).
+		SetLimit(limit)
+	cursor, err := f.DB.Collection(FORWARD_DEAD_LETTER_COLLECTION).Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	forwards := []*models.Forward{}
+	if err := cursor.All(context.TODO(), &forwards); err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return forwards, nil
+}
+
+func (f ForwardRepositoryDb) FindDeadLetterById(id string) (*models.Forward, error) {
+	var fw models.Forward
+	objId, _ := primitive.ObjectIDFromHex(id)
+	qry := bson.M{
+		"_id": objId,
+	}
+	if err := f.DB.Collection(FORWARD_DEAD_LETTER_COLLECTION).FindOne(context.TODO(), qry).Decode(&fw); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return &fw, nil
+}
+
+func (f ForwardRepositoryDb) DeleteDeadLetter(id string) error {
+	objId, _ := primitive.ObjectIDFromHex(id)
+	_, err := f.DB.Collection(FORWARD_DEAD_LETTER_COLLECTION).DeleteOne(context.TODO(), bson.M{"_id": objId})
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func NewForwardRepositoryDb(dbClient *mongo.Database) ForwardRepositoryDb {
+	return ForwardRepositoryDb{dbClient}
+}
@@ -0,0 +1,57 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/storage"
+)
+
+func TestForwardRepository(t *testing.T) {
+	db := storage.NewTestDB()
+	defer storage.CloseDB(db)
+	storage.CleanupDB(db)
+	repo := NewForwardRepositoryDb(db)
+
+	now := time.Now()
+	forward := &models.Forward{
+		ChannelUUID: "f11c744c-4937-4ee3-8a51-26e56eb77c4e",
+		Payload:     `{"messages":[]}`,
+		Attempts:    1,
+		NextAttempt: now.Add(-time.Second),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	err := repo.Insert(forward)
+	assert.Nil(t, err)
+
+	// test AcquireNext leases the due forward
+	acquired, err := repo.AcquireNext(now, time.Minute)
+	assert.Nil(t, err)
+	assert.Equal(t, forward.ID, acquired.ID)
+
+	acquired, err = repo.AcquireNext(now, time.Minute)
+	assert.Nil(t, err)
+	assert.Nil(t, acquired)
+
+	// test dead letter
+	err = repo.InsertDeadLetter(forward)
+	assert.Nil(t, err)
+	err = repo.Delete(forward.ID.Hex())
+	assert.Nil(t, err)
+
+	deadLetters, err := repo.FindDeadLetters(0, 10)
+	assert.Nil(t, err)
+	assert.Len(t, deadLetters, 1)
+
+	deadLetter, err := repo.FindDeadLetterById(forward.ID.Hex())
+	assert.Nil(t, err)
+	assert.Equal(t, forward.Payload, deadLetter.Payload)
+
+	err = repo.DeleteDeadLetter(forward.ID.Hex())
+	assert.Nil(t, err)
+	_, err = repo.FindDeadLetterById(forward.ID.Hex())
+	assert.Equal(t, ErrNotFound, err)
+}
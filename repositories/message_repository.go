@@ -0,0 +1,69 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+const MESSAGE_COLLECTION = "message"
+
+type MessageRepository interface {
+	Insert(*models.Message) error
+	FindByMessageID(string) (*models.Message, error)
+	CreateIndexes(time.Duration) error
+}
+
+type MessageRepositoryDb struct {
+	DB *mongo.Database
+}
+
+func (m MessageRepositoryDb) Insert(message *models.Message) error {
+	result, err := m.DB.Collection(MESSAGE_COLLECTION).InsertOne(context.TODO(), message)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		message.ID = id
+	}
+	return nil
+}
+
+func (m MessageRepositoryDb) FindByMessageID(messageID string) (*models.Message, error) {
+	var msg models.Message
+	qry := bson.M{
+		"message_id": messageID,
+	}
+	if err := m.DB.Collection(MESSAGE_COLLECTION).FindOne(context.TODO(), qry).Decode(&msg); err != nil {
+		return nil, fmt.Errorf("FindByMessageID failed, message not found for message_id=%s. Error: %s", messageID, err.Error())
+	}
+	return &msg, nil
+}
+
+// CreateIndexes creates the index of message_id used to find sent messages,
+// and the TTL index that expires sent messages after ttl.
+func (m MessageRepositoryDb) CreateIndexes(ttl time.Duration) error {
+	_, err := m.DB.Collection(MESSAGE_COLLECTION).Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys: bson.M{"message_id": 1},
+		},
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if err := createTTLIndex(m.DB.Collection(MESSAGE_COLLECTION), "created_at", ttl); err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func NewMessageRepositoryDb(dbClient *mongo.Database) MessageRepositoryDb {
+	return MessageRepositoryDb{dbClient}
+}
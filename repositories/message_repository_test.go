@@ -0,0 +1,35 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/storage"
+)
+
+func TestMessageRepository(t *testing.T) {
+	db := storage.NewTestDB()
+	defer storage.CloseDB(db)
+	repo := NewMessageRepositoryDb(db)
+
+	err := repo.CreateIndexes(24 * time.Hour)
+	assert.Nil(t, err)
+
+	msg := &models.Message{
+		MessageID: "gBEGVYKZRIIyAgmiTgezkroUL2Q",
+		URN:       "5582988887777",
+		Channel:   dummyChannel.ID,
+	}
+	err = repo.Insert(msg)
+	assert.Nil(t, err)
+	assert.False(t, msg.ID.IsZero())
+
+	found, err := repo.FindByMessageID(msg.MessageID)
+	assert.Nil(t, err)
+	assert.Equal(t, msg.Channel, found.Channel)
+
+	_, err = repo.FindByMessageID("not-found")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,91 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const PROCESSED_MESSAGE_COLLECTION = "processed_message"
+
+type ProcessedMessageRepository interface {
+	Insert(*models.ProcessedMessage) error
+	Delete(string) error
+	CreateIndexes(time.Duration) error
+}
+
+type ProcessedMessageRepositoryDb struct {
+	DB *mongo.Database
+}
+
+// Insert returns ErrDuplicate if the message was already inserted.
+func (p ProcessedMessageRepositoryDb) Insert(msg *models.ProcessedMessage) error {
+	_, err := p.DB.Collection(PROCESSED_MESSAGE_COLLECTION).InsertOne(context.TODO(), msg)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func (p ProcessedMessageRepositoryDb) Delete(id string) error {
+	_, err := p.DB.Collection(PROCESSED_MESSAGE_COLLECTION).DeleteOne(context.TODO(), bson.M{"_id": id})
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+// CreateIndexes creates the TTL index that expires processed messages after ttl.
+func (p ProcessedMessageRepositoryDb) CreateIndexes(ttl time.Duration) error {
+	if err := createTTLIndex(p.DB.Collection(PROCESSED_MESSAGE_COLLECTION), "created_at", ttl); err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+// createTTLIndex creates the index expiring the documents of the collection
+// ttl after the time of the field. When the index already exists with another
+// ttl, its expireAfterSeconds is updated in place with collMod, so the
+// collection is never left without the index.
+func createTTLIndex(coll *mongo.Collection, field string, ttl time.Duration) error {
+	seconds := int32(ttl.Seconds())
+	_, err := coll.Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys:    bson.D{{Key: field, Value: 1}},
+			Options: options.Index().SetExpireAfterSeconds(seconds),
+		},
+	)
+	if !isIndexOptionsConflict(err) {
+		return err
+	}
+	return coll.Database().RunCommand(context.TODO(), bson.D{
+		{Key: "collMod", Value: coll.Name()},
+		{Key: "index", Value: bson.D{
+			{Key: "keyPattern", Value: bson.D{{Key: field, Value: 1}}},
+			{Key: "expireAfterSeconds", Value: seconds},
+		}},
+	}).Err()
+}
+
+// isIndexOptionsConflict reports whether the error is due to an existing
+// index with the same keys and other options.
+func isIndexOptionsConflict(err error) bool {
+	var cmdErr mongo.CommandError
+	if errors.As(err, &cmdErr) {
+		return cmdErr.Code == 85 // IndexOptionsConflict
+	}
+	return false
+}
+
+func NewProcessedMessageRepositoryDb(dbClient *mongo.Database) ProcessedMessageRepositoryDb {
+	return ProcessedMessageRepositoryDb{dbClient}
+}
@@ -0,0 +1,59 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/storage"
+	"go.mongodb.org/mongo-driver/mongo"
+)
+
+func TestProcessedMessageRepository(t *testing.T) {
+	db := storage.NewTestDB()
+	defer storage.CloseDB(db)
+	repo := NewProcessedMessageRepositoryDb(db)
+
+	err := repo.CreateIndexes(24 * time.Hour)
+	assert.Nil(t, err)
+
+	// the ttl of the existing index is updated
+	err = repo.CreateIndexes(48 * time.Hour)
+	assert.Nil(t, err)
+	assert.Equal(t, int32(48*60*60), expireAfterSeconds(t, db.Collection(PROCESSED_MESSAGE_COLLECTION), "created_at_1"))
+
+	msg := &models.ProcessedMessage{
+		ID:        "ABGGFlA5FpafAgo6EhRBxk1VOq-l",
+		CreatedAt: time.Now(),
+	}
+	err = repo.Insert(msg)
+	assert.Nil(t, err)
+
+	err = repo.Insert(msg)
+	assert.Equal(t, ErrDuplicate, err)
+
+	err = repo.Delete(msg.ID)
+	assert.Nil(t, err)
+
+	err = repo.Insert(msg)
+	assert.Nil(t, err)
+}
+
+// expireAfterSeconds returns the ttl of the index of the collection.
+func expireAfterSeconds(t *testing.T, coll *mongo.Collection, name string) int32 {
+	cursor, err := coll.Indexes().List(context.TODO())
+	assert.Nil(t, err)
+	var indexes []struct {
+		Name               string `bson:"name"`
+		ExpireAfterSeconds int32  `bson:"expireAfterSeconds"`
+	}
+	assert.Nil(t, cursor.All(context.TODO(), &indexes))
+	for _, index := range indexes {
+		if index.Name == name {
+			return index.ExpireAfterSeconds
+		}
+	}
+	return 0
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const TEMPLATE_COLLECTION = "template"
+
+type TemplateRepository interface {
+	Upsert(*models.Template) error
+	FindOne(account string, name string, language string) (*models.Template, error)
+	FindAll(account string) ([]*models.Template, error)
+	Delete(account string, name string, language string) error
+	CreateIndexes() error
+}
+
+type TemplateRepositoryDb struct {
+	DB *mongo.Database
+}
+
+// Upsert saves the template, replacing the one of the account with the same
+// name and language.
+func (t TemplateRepositoryDb) Upsert(template *models.Template) error {
+	result, err := t.DB.Collection(TEMPLATE_COLLECTION).ReplaceOne(
+		context.TODO(),
+		templateFilter(template.Account, template.Name, template.Language),
+		template,
+		options.Replace().SetUpsert(true),
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if id, ok := result.UpsertedID.(primitive.ObjectID); ok {
+		template.ID = id
+		return nil
+	}
+	saved, err := t.FindOne(template.Account, template.Name, template.Language)
+	if err != nil {
+		return err
+	}
+	template.ID = saved.ID
+	return nil
+}
+
+func (t TemplateRepositoryDb) FindOne(account string, name string, language string) (*models.Template, error) {
+	var template models.Template
+	err := t.DB.Collection(TEMPLATE_COLLECTION).FindOne(
+		context.TODO(),
+		templateFilter(account, name, language),
+	).Decode(&template)
+	if err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return &template, nil
+}
+
+// FindAll returns the templates of the account sorted by name and language.
+func (t TemplateRepositoryDb) FindAll(account string) ([]*models.Template, error) {
+	opts := options.Find().SetSort(bson.D{{Key: "name", Value: 1}, {Key: "language", Value: 1}})
+	cursor, err := t.DB.Collection(TEMPLATE_COLLECTION).Find(
+		context.TODO(),
+		bson.M{"account": accountFilter(account)},
+		opts,
+	)
+	if err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	templates := []*models.Template{}
+	if err := cursor.All(context.TODO(), &templates); err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return templates, nil
+}
+
+func (t TemplateRepositoryDb) Delete(account string, name string, language string) error {
+	result, err := t.DB.Collection(TEMPLATE_COLLECTION).DeleteOne(
+		context.TODO(),
+		templateFilter(account, name, language),
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateIndexes creates the unique index of template name and language in
+// each whatsapp account.
+func (t TemplateRepositoryDb) CreateIndexes() error {
+	_, err := t.DB.Collection(TEMPLATE_COLLECTION).Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys: bson.D{
+				{Key: "account", Value: 1},
+				{Key: "name", Value: 1},
+				{Key: "language", Value: 1},
+			},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func templateFilter(account string, name string, language string) bson.M {
+	return bson.M{
+		"account":  accountFilter(account),
+		"name":     name,
+		"language": language,
+	}
+}
+
+func NewTemplateRepositoryDb(dbClient *mongo.Database) TemplateRepositoryDb {
+	return TemplateRepositoryDb{dbClient}
+}
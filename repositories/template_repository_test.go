@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/storage"
+)
+
+func TestTemplateRepository(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	templateRepository := NewTemplateRepositoryDb(mongodb)
+	err := templateRepository.CreateIndexes()
+	assert.Nil(t, err)
+
+	template := &models.Template{Name: "welcome", Language: "pt_BR", ParameterCount: 1}
+	err = templateRepository.Upsert(template)
+	assert.Nil(t, err)
+	assert.False(t, template.ID.IsZero())
+	err = templateRepository.Upsert(&models.Template{Name: "welcome", Language: "pt_BR", ParameterCount: 2})
+	assert.Nil(t, err)
+	err = templateRepository.Upsert(&models.Template{Account: "demo-2", Name: "welcome", Language: "pt_BR"})
+	assert.Nil(t, err)
+
+	found, err := templateRepository.FindOne("", "welcome", "pt_BR")
+	assert.Nil(t, err)
+	assert.Equal(t, template.ID, found.ID)
+	assert.Equal(t, 2, found.ParameterCount)
+
+	templates, err := templateRepository.FindAll("demo-2")
+	assert.Nil(t, err)
+	assert.Len(t, templates, 1)
+
+	err = templateRepository.Delete("", "welcome", "pt_BR")
+	assert.Nil(t, err)
+	_, err = templateRepository.FindOne("", "welcome", "pt_BR")
+	assert.Equal(t, ErrNotFound, err)
+	err = templateRepository.Delete("", "welcome", "pt_BR")
+	assert.Equal(t, ErrNotFound, err)
+}
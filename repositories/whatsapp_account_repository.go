@@ -0,0 +1,116 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+)
+
+const WHATSAPP_ACCOUNT_COLLECTION = "whatsapp_account"
+
+type WhatsappAccountRepository interface {
+	Insert(*models.WhatsappAccount) error
+	FindByName(string) (*models.WhatsappAccount, error)
+	FindAll() ([]*models.WhatsappAccount, error)
+	UpdateToken(string, string, time.Time) error
+	Delete(string) error
+	CreateIndexes() error
+}
+
+type WhatsappAccountRepositoryDb struct {
+	DB *mongo.Database
+}
+
+// Insert returns ErrDuplicate if there is an account with the same name.
+func (a WhatsappAccountRepositoryDb) Insert(account *models.WhatsappAccount) error {
+	result, err := a.DB.Collection(WHATSAPP_ACCOUNT_COLLECTION).InsertOne(context.TODO(), account)
+	if mongo.IsDuplicateKeyError(err) {
+		return ErrDuplicate
+	}
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if id, ok := result.InsertedID.(primitive.ObjectID); ok {
+		account.ID = id
+	}
+	return nil
+}
+
+func (a WhatsappAccountRepositoryDb) FindByName(name string) (*models.WhatsappAccount, error) {
+	var account models.WhatsappAccount
+	qry := bson.M{
+		"name": name,
+	}
+	if err := a.DB.Collection(WHATSAPP_ACCOUNT_COLLECTION).FindOne(context.TODO(), qry).Decode(&account); err != nil {
+		if err == mongo.ErrNoDocuments {
+			return nil, ErrNotFound
+		}
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return &account, nil
+}
+
+func (a WhatsappAccountRepositoryDb) FindAll() ([]*models.WhatsappAccount, error) {
+	opts := options.Find().SetSort(bson.M{"name": 1})
+	cursor, err := a.DB.Collection(WHATSAPP_ACCOUNT_COLLECTION).Find(context.TODO(), bson.M{}, opts)
+	if err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	accounts := []*models.WhatsappAccount{}
+	if err := cursor.All(context.TODO(), &accounts); err != nil {
+		return nil, errors.New("unexpected database error: " + err.Error())
+	}
+	return accounts, nil
+}
+
+// UpdateToken saves the auth token of the account with the given name.
+func (a WhatsappAccountRepositoryDb) UpdateToken(name string, token string, expiresAt time.Time) error {
+	qry := bson.M{
+		"name": name,
+	}
+	update := bson.M{"$set": bson.M{"token": token, "expires_at": expiresAt}}
+	result, err := a.DB.Collection(WHATSAPP_ACCOUNT_COLLECTION).UpdateOne(context.TODO(), qry, update)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if result.MatchedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+func (a WhatsappAccountRepositoryDb) Delete(name string) error {
+	result, err := a.DB.Collection(WHATSAPP_ACCOUNT_COLLECTION).DeleteOne(context.TODO(), bson.M{"name": name})
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	if result.DeletedCount == 0 {
+		return ErrNotFound
+	}
+	return nil
+}
+
+// CreateIndexes creates the unique index of account name.
+func (a WhatsappAccountRepositoryDb) CreateIndexes() error {
+	_, err := a.DB.Collection(WHATSAPP_ACCOUNT_COLLECTION).Indexes().CreateOne(
+		context.TODO(),
+		mongo.IndexModel{
+			Keys:    bson.M{"name": 1},
+			Options: options.Index().SetUnique(true),
+		},
+	)
+	if err != nil {
+		return errors.New("unexpected database error: " + err.Error())
+	}
+	return nil
+}
+
+func NewWhatsappAccountRepositoryDb(dbClient *mongo.Database) WhatsappAccountRepositoryDb {
+	return WhatsappAccountRepositoryDb{dbClient}
+}
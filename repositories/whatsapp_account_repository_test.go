@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/storage"
+)
+
+func TestWhatsappAccountRepository(t *testing.T) {
+	mongodb := storage.NewTestDB()
+	defer storage.CloseDB(mongodb)
+	storage.CleanupDB(mongodb)
+	accountRepository := NewWhatsappAccountRepositoryDb(mongodb)
+	err := accountRepository.CreateIndexes()
+	assert.Nil(t, err)
+
+	account := &models.WhatsappAccount{
+		Name:     "demo-2",
+		BaseURL:  "https://whatsapp-2.local",
+		Username: "admin",
+		Password: "secret",
+	}
+	err = accountRepository.Insert(account)
+	assert.Nil(t, err)
+	err = accountRepository.Insert(&models.WhatsappAccount{Name: "demo-2"})
+	assert.Equal(t, ErrDuplicate, err)
+
+	expiresAt := time.Now().Add(time.Hour).UTC().Truncate(time.Millisecond)
+	err = accountRepository.UpdateToken("demo-2", "account-token", expiresAt)
+	assert.Nil(t, err)
+	found, err := accountRepository.FindByName("demo-2")
+	assert.Nil(t, err)
+	assert.Equal(t, "account-token", found.Token)
+	assert.True(t, expiresAt.Equal(found.ExpiresAt))
+
+	accounts, err := accountRepository.FindAll()
+	assert.Nil(t, err)
+	assert.Len(t, accounts, 1)
+
+	err = accountRepository.Delete("demo-2")
+	assert.Nil(t, err)
+	_, err = accountRepository.FindByName("demo-2")
+	assert.Equal(t, ErrNotFound, err)
+	err = accountRepository.UpdateToken("demo-2", "account-token", expiresAt)
+	assert.Equal(t, ErrNotFound, err)
+}
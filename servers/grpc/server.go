@@ -34,7 +34,10 @@ func NewServer(db *mongo.Database, metrics *metric.Service) *Server {
 
 func (s *Server) Start() error {
 	chanelRepository := repositories.NewChannelRepositoryDb(s.Db)
-	channelService := services.NewChannelService(chanelRepository, s.metrics)
+	contactRepository := repositories.NewContactRepositoryDb(s.Db)
+	accountRepository := repositories.NewWhatsappAccountRepositoryDb(s.Db)
+	bindingRepository := repositories.NewContactBindingRepositoryDb(s.Db)
+	channelService := services.NewChannelService(chanelRepository, contactRepository, accountRepository, bindingRepository, s.metrics)
 	s.grpcServer = grpc.NewServer()
 	pb.RegisterChannelServiceServer(s.grpcServer, channelService)
 	reflection.Register(s.grpcServer)
@@ -0,0 +1,121 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/services"
+)
+
+type AccountsHandler struct {
+	Accounts services.WhatsappAccountService
+}
+
+func (h *AccountsHandler) HandleCreateAccount(w http.ResponseWriter, r *http.Request) {
+	account := &models.WhatsappAccount{}
+	if err := json.NewDecoder(r.Body).Decode(account); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	account, err := h.Accounts.CreateAccount(account)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrInvalidAccount):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		case errors.Is(err, repositories.ErrDuplicate):
+			http.Error(w, "whatsapp account already exists", http.StatusConflict)
+		default:
+			logger.Error(err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	hideSecrets(account)
+	writeJSON(w, http.StatusCreated, account)
+}
+
+func (h *AccountsHandler) HandleListAccounts(w http.ResponseWriter, r *http.Request) {
+	accounts, err := h.Accounts.ListAccounts()
+	if err != nil {
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	for _, account := range accounts {
+		hideSecrets(account)
+	}
+	writeJSON(w, http.StatusOK, accounts)
+}
+
+func (h *AccountsHandler) HandleDeleteAccount(w http.ResponseWriter, r *http.Request) {
+	name := chi.URLParam(r, "name")
+	if err := h.Accounts.DeleteAccount(name); err != nil {
+		whatsappAccountError(w, fmt.Errorf("whatsapp account %s %w", name, err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// hideSecrets blanks the credentials of the account, which are not returned
+// by the api.
+func hideSecrets(account *models.WhatsappAccount) {
+	account.Password = ""
+	account.AccessToken = ""
+	account.VerifyToken = ""
+	account.WebhookSecret = ""
+}
+
+// accountWhatsappService returns the whatsapp service of the account, the
+// default service being the one of the account configured by the environment.
+func accountWhatsappService(defaultService services.WhatsappService, accounts services.WhatsappAccountService, account string) (services.WhatsappService, error) {
+	if account == "" {
+		return defaultService, nil
+	}
+	ws, err := accounts.WhatsappService(account)
+	if err != nil {
+		return nil, fmt.Errorf("whatsapp account %s %w", account, err)
+	}
+	return ws, nil
+}
+
+// channelAccount returns the whatsapp account of the channel of the request,
+// or the default account if there is no channel.
+func channelAccount(r *http.Request, channels services.ChannelService) (string, error) {
+	ch, err := requestChannel(r, channels)
+	if err != nil || ch == nil {
+		return "", err
+	}
+	return ch.Account, nil
+}
+
+// requestChannel returns the authenticated channel or the channel in the
+// request path, or nil if the request has no channel.
+func requestChannel(r *http.Request, channels services.ChannelService) (*models.Channel, error) {
+	if ch := authenticatedChannel(r); ch != nil {
+		return ch, nil
+	}
+	uuid := chi.URLParam(r, "uuid")
+	if uuid == "" {
+		return nil, nil
+	}
+	ch, err := channels.FindChannel(&models.Channel{UUID: uuid})
+	if err != nil {
+		return nil, fmt.Errorf("channel %s %w", uuid, err)
+	}
+	return ch, nil
+}
+
+func whatsappAccountError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, err.Error(), http.StatusNotFound)
+		return
+	}
+	logger.Error(err.Error())
+	http.Error(w, err.Error(), http.StatusInternalServerError)
+}
@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/metric"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// fakeAccounts is a whatsapp account service keeping the accounts in memory.
+type fakeAccounts struct {
+	accounts map[string]*models.WhatsappAccount
+	services map[string]services.WhatsappService
+}
+
+func (f *fakeAccounts) CreateAccount(account *models.WhatsappAccount) (*models.WhatsappAccount, error) {
+	if account.Name == "" {
+		return nil, services.ErrInvalidAccount
+	}
+	if _, ok := f.accounts[account.Name]; ok {
+		return nil, repositories.ErrDuplicate
+	}
+	f.accounts[account.Name] = account
+	return account, nil
+}
+
+func (f *fakeAccounts) ListAccounts() ([]*models.WhatsappAccount, error) {
+	accounts := []*models.WhatsappAccount{}
+	for _, account := range f.accounts {
+		accounts = append(accounts, account)
+	}
+	return accounts, nil
+}
+
+func (f *fakeAccounts) DeleteAccount(name string) error {
+	if _, ok := f.accounts[name]; !ok {
+		return repositories.ErrNotFound
+	}
+	delete(f.accounts, name)
+	return nil
+}
+
+func (f *fakeAccounts) WhatsappService(name string) (services.WhatsappService, error) {
+	ws, ok := f.services[name]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return ws, nil
+}
+
+func (f *fakeAccounts) RefreshTokens() {}
+
+func newFakeAccounts() *fakeAccounts {
+	return &fakeAccounts{
+		accounts: map[string]*models.WhatsappAccount{},
+		services: map[string]services.WhatsappService{},
+	}
+}
+
+func TestHandleCreateAccount(t *testing.T) {
+	ah := AccountsHandler{Accounts: newFakeAccounts()}
+	router := chi.NewRouter()
+	router.Post("/integrations/account", ah.HandleCreateAccount)
+	router.Get("/integrations/account", ah.HandleListAccounts)
+
+	payload := `{"name":"acme","base_url":"https://acme.example.com","username":"admin","password":"secret"}`
+	for _, code := range []int{http.StatusCreated, http.StatusConflict} {
+		request, _ := http.NewRequest(http.MethodPost, "/integrations/account", strings.NewReader(payload))
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		assert.Equal(t, code, response.Code)
+		assert.NotContains(t, response.Body.String(), "secret")
+	}
+
+	request, _ := http.NewRequest(http.MethodGet, "/integrations/account", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var accounts []models.WhatsappAccount
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&accounts))
+	assert.Equal(t, []models.WhatsappAccount{{
+		Name:     "acme",
+		BaseURL:  "https://acme.example.com",
+		Username: "admin",
+	}}, accounts)
+}
+
+func TestHandleSendMessageChannelAccount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := []byte(`{"to":"5582988887777","type":"text","text":{"body":"hello"}}`)
+
+	channel := &models.Channel{ID: primitive.NewObjectID(), UUID: DummyCh.UUID, Account: "acme"}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: DummyCh.UUID}).Return(channel, nil)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777", Account: "acme"}).Return(
+		&models.Contact{URN: "5582988887777", Account: "acme", Channel: channel.ID}, nil,
+	)
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+	mockDefaultService := mocks.NewMockWhatsappService(ctrl)
+	mockAccountService := mocks.NewMockWhatsappService(ctrl)
+	mockAccountService.EXPECT().SendMessage(payload).Return(
+		http.Header{},
+		ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[]}`))),
+		nil,
+	)
+	accounts := newFakeAccounts()
+	accounts.services["acme"] = mockAccountService
+
+	ch := CourierHandler{
+		WhatsappService: mockDefaultService,
+		ChannelService:  mockChannelService,
+		ContactService:  mockContactService,
+		Accounts:        accounts,
+		Metrics:         metricService,
+	}
+	router := chi.NewRouter()
+	router.Post("/channel/{uuid}/v1/messages", ch.HandleSendMessage)
+
+	request, _ := http.NewRequest(
+		http.MethodPost,
+		"/channel/"+DummyCh.UUID+"/v1/messages",
+		bytes.NewReader(payload),
+	)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, http.StatusCreated, response.Code)
+}
@@ -0,0 +1,52 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/go-chi/chi"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/services"
+)
+
+type channelContextKey struct{}
+
+// ChannelAuth authenticates the requests of courier with the bearer
+// credential of the sending channel, which courier sends as the whatsapp auth
+// token. Requests under /channel/{uuid} must use the credential of that
+// channel.
+func ChannelAuth(channels services.ChannelService) func(http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			credential := strings.TrimSpace(strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer "))
+			ch, err := channels.FindChannelByCredential(credential)
+			if err != nil {
+				if !errors.Is(err, repositories.ErrNotFound) {
+					logger.Error(err.Error())
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				w.Header().Set("WWW-Authenticate", "Bearer")
+				http.Error(w, "invalid channel credential", http.StatusUnauthorized)
+				return
+			}
+			if uuid := chi.URLParam(r, "uuid"); uuid != "" && uuid != ch.UUID {
+				http.Error(w, "credential of another channel", http.StatusForbidden)
+				return
+			}
+			ctx := context.WithValue(r.Context(), channelContextKey{}, ch)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// authenticatedChannel returns the channel authenticated by ChannelAuth, or
+// nil if the request was not authenticated.
+func authenticatedChannel(r *http.Request) *models.Channel {
+	ch, _ := r.Context().Value(channelContextKey{}).(*models.Channel)
+	return ch
+}
@@ -0,0 +1,61 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+var tcChannelAuth = []struct {
+	Label         string
+	Path          string
+	Authorization string
+	Code          int
+}{
+	{"valid credential", "/v1/health", "Bearer Zm9vYmFyYmF6", 200},
+	{"valid credential of the channel in path", "/channel/" + DummyCh.UUID + "/v1/health", "Bearer Zm9vYmFyYmF6", 200},
+	{"credential of another channel", "/channel/9e8d7c6b-5a4f-4e3d-8c2b-1a0f9e8d7c6b/v1/health", "Bearer Zm9vYmFyYmF6", 403},
+	{"unknown credential", "/v1/health", "Bearer unknown", 401},
+	{"no credential", "/v1/health", "", 401},
+}
+
+func TestChannelAuth(t *testing.T) {
+	for _, tc := range tcChannelAuth {
+		t.Run(tc.Label, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			mockChannelService := mocks.NewMockChannelService(ctrl)
+			mockChannelService.EXPECT().FindChannelByCredential("Zm9vYmFyYmF6").Return(DummyCh, nil).AnyTimes()
+			mockChannelService.EXPECT().FindChannelByCredential(gomock.Not("Zm9vYmFyYmF6")).Return(nil, repositories.ErrNotFound).AnyTimes()
+
+			health := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, DummyCh, authenticatedChannel(r))
+				w.WriteHeader(http.StatusOK)
+			}
+			router := chi.NewRouter()
+			router.Route("/v1", func(r chi.Router) {
+				r.Use(ChannelAuth(mockChannelService))
+				r.Get("/health", health)
+			})
+			router.Route("/channel/{uuid}/v1", func(r chi.Router) {
+				r.Use(ChannelAuth(mockChannelService))
+				r.Get("/health", health)
+			})
+
+			request, _ := http.NewRequest(http.MethodGet, tc.Path, nil)
+			if tc.Authorization != "" {
+				request.Header.Set("Authorization", tc.Authorization)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+			assert.Equal(t, tc.Code, response.Code)
+		})
+	}
+}
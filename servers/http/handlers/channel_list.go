@@ -0,0 +1,166 @@
+package handlers
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var channelListConfig = config.GetConfig().Whatsapp.ChannelList
+
+const (
+	// channelRowPrefix prefixes the channel uuid in the ids of the rows of the
+	// channel list, telling its replies apart from other list replies.
+	channelRowPrefix = "channel:"
+	// maxListRows and maxRowTitle are the limits of whatsapp list messages.
+	maxListRows = 10
+	maxRowTitle = 24
+	// bindingsPageLimit is how many bindings are read at a time when looking
+	// for the recent channels of a contact.
+	bindingsPageLimit = 50
+)
+
+// sendChannelList sends to the contact an interactive list of the channels it
+// was recently bound to, or the empty list message if there are none.
+func (h *WhatsappHandler) sendChannelList(ws services.WhatsappService, contact *models.Contact) error {
+	channels, err := h.recentChannels(contact)
+	if err != nil {
+		return err
+	}
+	if len(channels) == 0 {
+		return sendText(ws, contact.URN, channelListConfig.EmptyMessage)
+	}
+	payload, err := json.Marshal(channelListMessage(contact.URN, channels))
+	if err != nil {
+		return err
+	}
+	return sendPayload(ws, payload)
+}
+
+// recentChannels returns the channels of the account of the contact it was
+// last bound to, most recent first, skipping the deleted ones.
+func (h *WhatsappHandler) recentChannels(contact *models.Contact) ([]*models.Channel, error) {
+	size := channelListConfig.Size
+	if size <= 0 || size > maxListRows {
+		size = maxListRows
+	}
+	seen := map[primitive.ObjectID]bool{}
+	var channels []*models.Channel
+	for page := int64(1); len(channels) < size; page++ {
+		bindings, err := h.BindingService.ListBindings(contact.URN, contact.Account, page, bindingsPageLimit)
+		if err != nil {
+			return nil, err
+		}
+		for _, b := range bindings {
+			if b.Event != models.BindingBind || b.Channel.IsZero() || seen[b.Channel] {
+				continue
+			}
+			seen[b.Channel] = true
+			channel, err := h.ChannelService.FindChannelById(b.Channel.Hex())
+			if err != nil {
+				logger.Debug(err.Error())
+				continue
+			}
+			if channel.Account != contact.Account {
+				continue
+			}
+			channels = append(channels, channel)
+			if len(channels) == size {
+				break
+			}
+		}
+		if len(bindings) < bindingsPageLimit {
+			break
+		}
+	}
+	return channels, nil
+}
+
+// chosenChannel returns the channel with the uuid chosen in the channel list,
+// or nil if it does not exist or is not a channel of the account.
+func (h *WhatsappHandler) chosenChannel(uuid string, account string) *models.Channel {
+	channel, err := h.ChannelService.FindChannel(&models.Channel{UUID: uuid})
+	if err != nil {
+		logger.Debug(err.Error())
+		return nil
+	}
+	if channel.Account != account {
+		logger.Debug("channel of the channel list reply of another whatsapp account")
+		return nil
+	}
+	return channel
+}
+
+// channelChoice returns the uuid of the channel chosen in a reply to the
+// channel list.
+func (m eventMessage) channelChoice() (string, bool) {
+	if m.Type != "interactive" || m.Interactive.Type != "list_reply" {
+		return "", false
+	}
+	id := m.Interactive.ListReply.ID
+	if !strings.HasPrefix(id, channelRowPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(id, channelRowPrefix), true
+}
+
+func channelListMessage(urn string, channels []*models.Channel) interactiveMessage {
+	rows := make([]listRow, 0, len(channels))
+	for _, channel := range channels {
+		title := channel.Name
+		if title == "" {
+			title = channel.UUID
+		}
+		if r := []rune(title); len(r) > maxRowTitle {
+			title = string(r[:maxRowTitle])
+		}
+		rows = append(rows, listRow{ID: channelRowPrefix + channel.UUID, Title: title})
+	}
+	return interactiveMessage{
+		To:   urn,
+		Type: "interactive",
+		Interactive: interactiveList{
+			Type: "list",
+			Body: interactiveText{Text: channelListConfig.Body},
+			Action: listAction{
+				Button:   channelListConfig.Button,
+				Sections: []listSection{{Rows: rows}},
+			},
+		},
+	}
+}
+
+type interactiveMessage struct {
+	To          string          `json:"to"`
+	Type        string          `json:"type"`
+	Interactive interactiveList `json:"interactive"`
+}
+
+type interactiveList struct {
+	Type   string          `json:"type"`
+	Body   interactiveText `json:"body"`
+	Action listAction      `json:"action"`
+}
+
+type interactiveText struct {
+	Text string `json:"text"`
+}
+
+type listAction struct {
+	Button   string        `json:"button"`
+	Sections []listSection `json:"sections"`
+}
+
+type listSection struct {
+	Rows []listRow `json:"rows"`
+}
+
+type listRow struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
@@ -0,0 +1,153 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/metric"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestChannelListKeyword(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	incomingRequest := `{"contacts":[{"profile":{"name":"Dummy"},"wa_id":"5582988887777"}],"messages":[{"from":"5582988887777","id":"123456","text":{"body":" Canais "},"timestamp":"623123123123","type":"text"}]}`
+	current := &models.Channel{ID: primitive.NewObjectID(), UUID: "6d0bd2fe-0e36-4c0d-a53a-4b9ea8d32b50", Name: "Support flow with a very long name"}
+	previous := &models.Channel{ID: primitive.NewObjectID(), UUID: "0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1", Name: "Sales"}
+	deleted := primitive.NewObjectID()
+	contact := &models.Contact{URN: "5582988887777", Name: "Dummy", Channel: current.ID}
+	payload, err := json.Marshal(channelListMessage(contact.URN, []*models.Channel{current, previous}))
+	assert.NoError(t, err)
+
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().RecordInbound(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockBindingService := mocks.NewMockContactBindingService(ctrl)
+	mockMessageService := mocks.NewMockMessageService(ctrl)
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockContactService.EXPECT().FindContact(gomock.Any()).Return(contact, nil)
+	mockBindingService.EXPECT().ListBindings("5582988887777", "", int64(1), int64(bindingsPageLimit)).Return(
+		[]*models.ContactBinding{
+			{Event: models.BindingBind, Channel: current.ID, PreviousChannel: deleted},
+			{Event: models.BindingUnbind, PreviousChannel: previous.ID},
+			{Event: models.BindingBind, Channel: deleted},
+			{Event: models.BindingBind, Channel: previous.ID},
+			{Event: models.BindingBind, Channel: current.ID},
+		}, nil,
+	)
+	mockChannelService.EXPECT().FindChannelById(current.ID.Hex()).Return(current, nil)
+	mockChannelService.EXPECT().FindChannelById(previous.ID.Hex()).Return(previous, nil)
+	mockChannelService.EXPECT().FindChannelById(deleted.Hex()).Return(nil, repositories.ErrNotFound)
+	mockWhatsappService.EXPECT().SendMessage(payload).Return(
+		http.Header{"content-type": {"application/json"}},
+		io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		nil,
+	)
+	mockMessageService.EXPECT().MarkAsProcessed(gomock.Any()).Return(true, nil).AnyTimes()
+
+	wh := WhatsappHandler{
+		ContactService:  mockContactService,
+		BindingService:  mockBindingService,
+		ChannelService:  mockChannelService,
+		CourierService:  mocks.NewMockCourierService(ctrl),
+		WhatsappService: mockWhatsappService,
+		MessageService:  mockMessageService,
+		Metrics:         metricService,
+	}
+	router := chi.NewRouter()
+	router.Post("/wr/receive/", wh.HandleIncomingRequests)
+	request, _ := http.NewRequest(http.MethodPost, "/wr/receive/", strings.NewReader(incomingRequest))
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+
+	var sent interactiveMessage
+	assert.NoError(t, json.Unmarshal(payload, &sent))
+	rows := sent.Interactive.Action.Sections[0].Rows
+	assert.Equal(t, "channel:"+current.UUID, rows[0].ID)
+	assert.Equal(t, "Support flow with a very", rows[0].Title)
+	assert.Equal(t, "Sales", rows[1].Title)
+}
+
+func TestChannelListReply(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	chosen := &models.Channel{ID: primitive.NewObjectID(), UUID: "0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1", Name: "Sales"}
+	otherAccount := &models.Channel{ID: primitive.NewObjectID(), UUID: "9e3f8a0b-6a51-4b2c-8f7d-1c2d3e4f5a6b", Account: "acme"}
+	listReply := func(id string, uuid string) string {
+		return `{"from":"5582988887777","id":"` + id + `","interactive":{"type":"list_reply","list_reply":{"id":"channel:` + uuid + `","title":"Sales"}},"timestamp":"623123123123","type":"interactive"}`
+	}
+	incomingRequest := `{"contacts":[{"profile":{"name":"Dummy"},"wa_id":"5582988887777"}],"messages":[` +
+		listReply("123456", otherAccount.UUID) + `,` + listReply("123457", chosen.UUID) + `]}`
+	contact := &models.Contact{URN: "5582988887777", Name: "Dummy", Channel: dummyChannel.ID}
+	payload, err := json.Marshal(textMessage{
+		To:   contact.URN,
+		Type: "text",
+		Text: textBody{Body: confirmationMessage},
+	})
+	assert.NoError(t, err)
+
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().RecordInbound(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+	mockBindingService := mocks.NewMockContactBindingService(ctrl)
+	mockCourierService := mocks.NewMockCourierService(ctrl)
+	mockMessageService := mocks.NewMockMessageService(ctrl)
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockContactService.EXPECT().FindContact(gomock.Any()).Return(contact, nil)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: otherAccount.UUID}).Return(otherAccount, nil)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: chosen.UUID}).Return(chosen, nil)
+	mockChannelService.EXPECT().FindChannelById(dummyChannel.ID.Hex()).Return(dummyChannel, nil).Times(2)
+	// the reply with the channel of another account is redirected as any other message
+	mockCourierService.EXPECT().RedirectMessage(dummyChannel.UUID, gomock.Any()).Return(http.StatusOK, nil)
+	mockContactService.EXPECT().UpdateContact(&models.Contact{URN: "5582988887777", Name: "Dummy", Channel: chosen.ID}).Return(contact, nil)
+	mockBindingService.EXPECT().RecordBinding(&models.ContactBinding{
+		URN:             "5582988887777",
+		Event:           models.BindingBind,
+		Channel:         chosen.ID,
+		PreviousChannel: dummyChannel.ID,
+		MessageID:       "123457",
+		Reason:          models.BindingReasonList,
+	}).Return(nil)
+	mockWhatsappService.EXPECT().SendMessage(payload).Return(
+		http.Header{"content-type": {"application/json"}},
+		io.NopCloser(bytes.NewReader([]byte(`{}`))),
+		nil,
+	)
+	mockMessageService.EXPECT().MarkAsProcessed(gomock.Any()).Return(true, nil).AnyTimes()
+
+	wh := WhatsappHandler{
+		ContactService:  mockContactService,
+		BindingService:  mockBindingService,
+		ChannelService:  mockChannelService,
+		CourierService:  mockCourierService,
+		WhatsappService: mockWhatsappService,
+		MessageService:  mockMessageService,
+		Metrics:         metricService,
+	}
+	router := chi.NewRouter()
+	router.Post("/wr/receive/", wh.HandleIncomingRequests)
+	request, _ := http.NewRequest(http.MethodPost, "/wr/receive/", strings.NewReader(incomingRequest))
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+}
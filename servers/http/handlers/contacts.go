@@ -0,0 +1,212 @@
+package handlers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/metric"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ContactsHandler manages the contacts of the account of the account query
+// param, or of the default account.
+type ContactsHandler struct {
+	ContactService services.ContactService
+	BindingService services.ContactBindingService
+	ChannelService services.ChannelService
+	Metrics        *metric.Service
+}
+
+// contactResponse is a contact with the state of its customer service window.
+type contactResponse struct {
+	*models.Contact
+	ServiceWindow serviceWindow `json:"service_window"`
+}
+
+type serviceWindow struct {
+	Open      bool       `json:"open"`
+	ExpiresAt *time.Time `json:"expires_at,omitempty"`
+}
+
+func newContactResponse(contact *models.Contact) contactResponse {
+	res := contactResponse{
+		Contact:       contact,
+		ServiceWindow: serviceWindow{Open: contact.InServiceWindow(time.Now())},
+	}
+	if expiresAt := contact.ServiceWindowExpiresAt(); !expiresAt.IsZero() {
+		res.ServiceWindow.ExpiresAt = &expiresAt
+	}
+	return res
+}
+
+// HandleListContacts returns a page of the contacts, of the channel of the
+// channel query param if it is given.
+func (h *ContactsHandler) HandleListContacts(w http.ResponseWriter, r *http.Request) {
+	page, limit, err := paginationParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	filter := &models.Contact{Account: r.URL.Query().Get("account")}
+	if uuid := r.URL.Query().Get("channel"); uuid != "" {
+		channel, err := h.ChannelService.FindChannel(&models.Channel{UUID: uuid})
+		if err != nil {
+			whatsappAccountError(w, fmt.Errorf("channel %s %w", uuid, err))
+			return
+		}
+		filter.Account = channel.Account
+		filter.Channel = channel.ID
+	}
+	contacts, err := h.ContactService.ListContacts(filter, page, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	res := make([]contactResponse, 0, len(contacts))
+	for _, contact := range contacts {
+		res = append(res, newContactResponse(contact))
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func (h *ContactsHandler) HandleGetContact(w http.ResponseWriter, r *http.Request) {
+	contact, ok := h.findContact(w, r)
+	if !ok {
+		return
+	}
+	writeJSON(w, http.StatusOK, newContactResponse(contact))
+}
+
+// HandleReassignContact binds the contact to the channel of the request body,
+// which must be a channel of the whatsapp account of the contact.
+func (h *ContactsHandler) HandleReassignContact(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Channel string `json:"channel"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Channel == "" {
+		http.Error(w, "channel uuid could not be empty", http.StatusBadRequest)
+		return
+	}
+	contact, ok := h.findContact(w, r)
+	if !ok {
+		return
+	}
+	channel, err := h.ChannelService.FindChannel(&models.Channel{UUID: req.Channel})
+	if err != nil {
+		whatsappAccountError(w, fmt.Errorf("channel %s %w", req.Channel, err))
+		return
+	}
+	if channel.Account != contact.Account {
+		http.Error(w, fmt.Sprintf("channel %s is not of the whatsapp account of contact %s", channel.UUID, contact.URN), http.StatusBadRequest)
+		return
+	}
+	if channel.ID == contact.Channel {
+		writeJSON(w, http.StatusOK, newContactResponse(contact))
+		return
+	}
+
+	previous := contact.Channel
+	contact.Channel = channel.ID
+	if _, err := h.ContactService.UpdateContact(contact); err != nil {
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	recordBinding(h.BindingService, &models.ContactBinding{
+		URN:             contact.URN,
+		Account:         contact.Account,
+		Event:           models.BindingBind,
+		Channel:         channel.ID,
+		PreviousChannel: previous,
+		Reason:          models.BindingReasonAPI,
+	})
+	h.decContactActivated(previous)
+	h.Metrics.IncContactActivated(metric.NewContactActivated(channel.UUID))
+	writeJSON(w, http.StatusOK, newContactResponse(contact))
+}
+
+// HandleUnbindContact unbinds the contact from its channel, so its messages are
+// no longer redirected until a new token is sent.
+func (h *ContactsHandler) HandleUnbindContact(w http.ResponseWriter, r *http.Request) {
+	contact, ok := h.findContact(w, r)
+	if !ok {
+		return
+	}
+	if !contact.Channel.IsZero() {
+		if _, err := h.ContactService.UnbindContact(contact); err != nil {
+			whatsappAccountError(w, fmt.Errorf("contact %s %w", contact.URN, err))
+			return
+		}
+		recordBinding(h.BindingService, &models.ContactBinding{
+			URN:             contact.URN,
+			Account:         contact.Account,
+			Event:           models.BindingUnbind,
+			PreviousChannel: contact.Channel,
+			Reason:          models.BindingReasonAPI,
+		})
+		h.decContactActivated(contact.Channel)
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleListBindings returns a page of the history of the channels the contact
+// of the urn was bound to, most recent first.
+func (h *ContactsHandler) HandleListBindings(w http.ResponseWriter, r *http.Request) {
+	page, limit, err := paginationParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	urn := chi.URLParam(r, "urn")
+	bindings, err := h.BindingService.ListBindings(urn, r.URL.Query().Get("account"), page, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, bindings)
+}
+
+// findContact returns the contact with the urn of the path, writing the error
+// to the response if it can not be found.
+func (h *ContactsHandler) findContact(w http.ResponseWriter, r *http.Request) (*models.Contact, bool) {
+	urn := chi.URLParam(r, "urn")
+	contact, err := h.ContactService.FindContact(&models.Contact{
+		URN:     urn,
+		Account: r.URL.Query().Get("account"),
+	})
+	if err != nil {
+		whatsappAccountError(w, fmt.Errorf("contact %s %w", urn, err))
+		return nil, false
+	}
+	return contact, true
+}
+
+// decContactActivated decrements the contacts activated in the channel the
+// contact was bound to, if it still exists.
+func (h *ContactsHandler) decContactActivated(channelID primitive.ObjectID) {
+	if channelID.IsZero() {
+		return
+	}
+	channel, err := h.ChannelService.FindChannelById(channelID.Hex())
+	if err != nil {
+		logger.Debug(err.Error())
+		return
+	}
+	h.Metrics.DecContactActivated(metric.NewContactActivated(channel.UUID))
+}
+
+// recordBinding appends the binding to the history of the contact. The change
+// of channel is already saved, so failing to record it is only logged.
+func recordBinding(bs services.ContactBindingService, binding *models.ContactBinding) {
+	if err := bs.RecordBinding(binding); err != nil {
+		logger.Error(fmt.Sprintf("unable to record %s of contact %s: %s", binding.Event, binding.URN, err))
+	}
+}
@@ -0,0 +1,189 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/metric"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestHandleGetContact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	lastInbound := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777", Account: "acme"}).Return(
+		&models.Contact{URN: "5582988887777", Account: "acme", LastInboundAt: lastInbound}, nil,
+	)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582900000000"}).Return(nil, repositories.ErrNotFound)
+
+	ch := ContactsHandler{ContactService: mockContactService}
+	router := chi.NewRouter()
+	router.Get("/integrations/contact/{urn}", ch.HandleGetContact)
+
+	request, _ := http.NewRequest(http.MethodGet, "/integrations/contact/5582988887777?account=acme", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var contact struct {
+		URN           string `json:"urn"`
+		ServiceWindow struct {
+			Open      bool      `json:"open"`
+			ExpiresAt time.Time `json:"expires_at"`
+		} `json:"service_window"`
+	}
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&contact))
+	assert.Equal(t, "5582988887777", contact.URN)
+	assert.True(t, contact.ServiceWindow.Open)
+	assert.True(t, lastInbound.Add(24*time.Hour).Equal(contact.ServiceWindow.ExpiresAt))
+
+	request, _ = http.NewRequest(http.MethodGet, "/integrations/contact/5582900000000", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotFound, response.Code)
+}
+
+func TestHandleListContacts(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channel := &models.Channel{ID: primitive.NewObjectID(), UUID: DummyCh.UUID, Account: "acme"}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: DummyCh.UUID}).Return(channel, nil)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().ListContacts(&models.Contact{Account: "acme", Channel: channel.ID}, int64(2), int64(10)).Return(
+		[]*models.Contact{{URN: "5582988887777", Account: "acme", Channel: channel.ID}}, nil,
+	)
+
+	ch := ContactsHandler{ContactService: mockContactService, ChannelService: mockChannelService}
+	router := chi.NewRouter()
+	router.Get("/integrations/contact", ch.HandleListContacts)
+
+	request, _ := http.NewRequest(http.MethodGet, "/integrations/contact?channel="+DummyCh.UUID+"&page=2&limit=10", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var contacts []models.Contact
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&contacts))
+	assert.Len(t, contacts, 1)
+	assert.Equal(t, channel.ID, contacts[0].Channel)
+}
+
+func TestHandleReassignContact(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	current := &models.Channel{ID: primitive.NewObjectID(), UUID: "6d0bd2fe-0e36-4c0d-a53a-4b9ea8d32b50"}
+	next := &models.Channel{ID: primitive.NewObjectID(), UUID: "0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1"}
+	otherAccount := &models.Channel{ID: primitive.NewObjectID(), UUID: "9e3f8a0b-6a51-4b2c-8f7d-1c2d3e4f5a6b", Account: "acme"}
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: next.UUID}).Return(next, nil)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: otherAccount.UUID}).Return(otherAccount, nil)
+	mockChannelService.EXPECT().FindChannelById(current.ID.Hex()).Return(current, nil).Times(2)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777"}).DoAndReturn(func(*models.Contact) (*models.Contact, error) {
+		return &models.Contact{URN: "5582988887777", Channel: current.ID}, nil
+	}).Times(3)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582900000000"}).Return(nil, repositories.ErrNotFound)
+	mockContactService.EXPECT().UpdateContact(&models.Contact{URN: "5582988887777", Channel: next.ID}).Return(
+		&models.Contact{URN: "5582988887777", Channel: next.ID}, nil,
+	)
+	mockContactService.EXPECT().UnbindContact(&models.Contact{URN: "5582988887777", Channel: current.ID}).Return(
+		&models.Contact{URN: "5582988887777"}, nil,
+	)
+	mockBindingService := mocks.NewMockContactBindingService(ctrl)
+	mockBindingService.EXPECT().RecordBinding(&models.ContactBinding{
+		URN:             "5582988887777",
+		Event:           models.BindingBind,
+		Channel:         next.ID,
+		PreviousChannel: current.ID,
+		Reason:          models.BindingReasonAPI,
+	}).Return(nil)
+	mockBindingService.EXPECT().RecordBinding(&models.ContactBinding{
+		URN:             "5582988887777",
+		Event:           models.BindingUnbind,
+		PreviousChannel: current.ID,
+		Reason:          models.BindingReasonAPI,
+	}).Return(nil)
+
+	ch := ContactsHandler{
+		ContactService: mockContactService,
+		BindingService: mockBindingService,
+		ChannelService: mockChannelService,
+		Metrics:        metricService,
+	}
+	router := chi.NewRouter()
+	router.Put("/integrations/contact/{urn}/channel", ch.HandleReassignContact)
+	router.Delete("/integrations/contact/{urn}/channel", ch.HandleUnbindContact)
+
+	tcs := []struct {
+		Label   string
+		Method  string
+		URN     string
+		Payload string
+		Code    int
+	}{
+		{"reassign", http.MethodPut, "5582988887777", `{"channel":"` + next.UUID + `"}`, http.StatusOK},
+		{"channel of another account", http.MethodPut, "5582988887777", `{"channel":"` + otherAccount.UUID + `"}`, http.StatusBadRequest},
+		{"no channel", http.MethodPut, "5582988887777", `{}`, http.StatusBadRequest},
+		{"unknown contact", http.MethodPut, "5582900000000", `{"channel":"` + next.UUID + `"}`, http.StatusNotFound},
+		{"unbind", http.MethodDelete, "5582988887777", "", http.StatusNoContent},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Label, func(t *testing.T) {
+			request, _ := http.NewRequest(tc.Method, "/integrations/contact/"+tc.URN+"/channel", strings.NewReader(tc.Payload))
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+			assert.Equal(t, tc.Code, response.Code)
+		})
+	}
+}
+
+func TestHandleListBindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	channel := primitive.NewObjectID()
+	mockBindingService := mocks.NewMockContactBindingService(ctrl)
+	mockBindingService.EXPECT().ListBindings("5582988887777", "acme", int64(1), int64(20)).Return(
+		[]*models.ContactBinding{
+			{URN: "5582988887777", Account: "acme", Event: models.BindingUnbind, PreviousChannel: channel, MessageID: "123457", Reason: models.BindingReasonStop},
+			{URN: "5582988887777", Account: "acme", Event: models.BindingBind, Channel: channel, MessageID: "123456", Reason: models.BindingReasonToken},
+		}, nil,
+	)
+
+	ch := ContactsHandler{BindingService: mockBindingService}
+	router := chi.NewRouter()
+	router.Get("/integrations/contact/{urn}/bindings", ch.HandleListBindings)
+
+	request, _ := http.NewRequest(http.MethodGet, "/integrations/contact/5582988887777/bindings?account=acme", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	var bindings []models.ContactBinding
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&bindings))
+	assert.Len(t, bindings, 2)
+	assert.Equal(t, models.BindingUnbind, bindings[0].Event)
+	assert.Equal(t, "123456", bindings[1].MessageID)
+	assert.Equal(t, channel, bindings[1].Channel)
+
+	request, _ = http.NewRequest(http.MethodGet, "/integrations/contact/5582988887777/bindings?page=0", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusBadRequest, response.Code)
+}
@@ -1,25 +1,164 @@
 package handlers
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
+	"strconv"
+	"time"
 
+	"github.com/weni/whatsapp-router/config"
 	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/metric"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
 	"github.com/weni/whatsapp-router/services"
 	"github.com/weni/whatsapp-router/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
+// outboundScoping is what is done with messages sent by a channel to contacts
+// not bound to it: they are rejected, or sent logging a warning.
+var outboundScoping = config.GetConfig().App.OutboundScoping
+
+const outboundScopingWarn = "warn"
+
+// reengagement is the template sent instead of free-form messages to contacts
+// out of the customer service window, if configured.
+var reengagement = config.GetConfig().Whatsapp.Reengagement
+
 type CourierHandler struct {
 	WhatsappService services.WhatsappService
+	ContactService  services.ContactService
+	MessageService  services.MessageService
+	ChannelService  services.ChannelService
+	Accounts        services.WhatsappAccountService
+	TemplateService services.TemplateService
+	Metrics         *metric.Service
 }
 
+// HandleSendMessage sends the message in the request body, as courier sends
+// it to the whatsapp api.
 func (c *CourierHandler) HandleSendMessage(w http.ResponseWriter, r *http.Request) {
+	channel, account, ws, ok := c.outbound(w, r)
+	if !ok {
+		return
+	}
 	bodyBytes, _ := ioutil.ReadAll(r.Body)
 	bodyString := string(bodyBytes)
 	logger.Debug(bodyString)
-	header, body, err := c.WhatsappService.SendMessage(bodyBytes)
+	c.send(w, channel, account, ws, bodyBytes)
+}
+
+// HandleSendTemplate sends a message of a registered template of the account,
+// responding 404 if there is no such template and 400 if it can not be sent
+// or its parameters do not match.
+func (c *CourierHandler) HandleSendTemplate(w http.ResponseWriter, r *http.Request) {
+	channel, account, ws, ok := c.outbound(w, r)
+	if !ok {
+		return
+	}
+	msg := &services.TemplateMessage{}
+	if err := json.NewDecoder(r.Body).Decode(msg); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	bodyBytes, err := c.TemplateService.TemplateMessage(account, msg)
+	if err != nil {
+		switch {
+		case errors.Is(err, repositories.ErrNotFound):
+			http.Error(w, err.Error(), http.StatusNotFound)
+		case errors.Is(err, services.ErrInvalidTemplateMessage):
+			http.Error(w, err.Error(), http.StatusBadRequest)
+		default:
+			logger.Error(err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+		return
+	}
+	logger.Debug(string(bodyBytes))
+	c.send(w, channel, account, ws, bodyBytes)
+}
 
+// outbound returns the sending channel of the request, identified by its
+// credential or by the request path, and the whatsapp account and service of
+// the channel, or the default ones if there is no channel. The error is
+// written to the response when they can not be found.
+func (c *CourierHandler) outbound(w http.ResponseWriter, r *http.Request) (*models.Channel, string, services.WhatsappService, bool) {
+	channel, err := requestChannel(r, c.ChannelService)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return nil, "", nil, false
+	}
+	var account string
+	if channel != nil {
+		account = channel.Account
+	}
+	ws, err := accountWhatsappService(c.WhatsappService, c.Accounts, account)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return nil, "", nil, false
+	}
+	return channel, account, ws, true
+}
+
+// send sends the message with the whatsapp service. Messages of a channel to
+// contacts not bound to it are rejected with 403, messages exceeding the send
+// rate of the account with 429, and free-form messages to contacts out of the
+// customer service window with 422, unless a re-engagement template is sent
+// instead.
+func (c *CourierHandler) send(w http.ResponseWriter, channel *models.Channel, account string, ws services.WhatsappService, bodyBytes []byte) {
+	var err error
+	var req sendMessageRequest
+	if err := json.Unmarshal(bodyBytes, &req); err != nil {
+		logger.Debug(fmt.Sprintf("message without recipient: %s", err))
+	}
+	recipient := c.findRecipient(req.To, account)
+	if channel != nil {
+		if err := checkRecipient(channel, req.To, recipient); err != nil {
+			if outboundScoping != outboundScopingWarn {
+				logger.Debug(err.Error())
+				c.Metrics.SaveOutboundMessage(metric.NewOutboundMessage(channel.UUID, metric.OutboundRejected))
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			logger.Info(fmt.Sprintf("warning: %s", err))
+		}
+		ws = services.ChannelWhatsappService(ws, channel.UUID)
+	}
+	if recipient != nil && !req.isTemplate() && !recipient.InServiceWindow(time.Now()) {
+		if reengagement.Template == "" {
+			logger.Debug(fmt.Sprintf("service window of %s closed at %s", req.To, recipient.ServiceWindowExpiresAt()))
+			writeJSON(w, http.StatusUnprocessableEntity, serviceWindowError(recipient))
+			return
+		}
+		logger.Info(fmt.Sprintf("service window of %s closed, sending re-engagement template %s", req.To, reengagement.Template))
+		bodyBytes, err = reengagementMessage(req.To)
+		if err != nil {
+			logger.Error(err.Error())
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	header, body, err := ws.SendMessage(bodyBytes)
+
+	var rateLimitErr *services.RateLimitError
+	if errors.As(err, &rateLimitErr) {
+		logger.Debug(err.Error())
+		if channel != nil {
+			c.Metrics.SaveOutboundMessage(metric.NewOutboundMessage(channel.UUID, metric.OutboundThrottled))
+		}
+		retryAfter := int(math.Ceil(rateLimitErr.RetryAfter.Seconds()))
+		if retryAfter < 1 {
+			retryAfter = 1
+		}
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfter))
+		http.Error(w, err.Error(), http.StatusTooManyRequests)
+		return
+	}
 	if err != nil {
 		logger.Error(err.Error())
 		w.WriteHeader(http.StatusInternalServerError)
@@ -29,6 +168,115 @@ func (c *CourierHandler) HandleSendMessage(w http.ResponseWriter, r *http.Reques
 
 	utils.CopyHeader(w.Header(), header)
 	b, _ := ioutil.ReadAll(body)
+	if channel != nil {
+		c.Metrics.SaveOutboundMessage(metric.NewOutboundMessage(channel.UUID, metric.OutboundSent))
+	}
+	c.saveSentMessages(channel, recipient, req.To, b)
 	w.WriteHeader(http.StatusCreated)
 	w.Write(b)
 }
+
+// findRecipient returns the contact of the account the message is sent to, or
+// nil if it is not a known contact.
+func (c *CourierHandler) findRecipient(urn string, account string) *models.Contact {
+	if urn == "" {
+		return nil
+	}
+	contact, err := c.ContactService.FindContact(&models.Contact{URN: urn, Account: account})
+	if err != nil {
+		logger.Debug(err.Error())
+		return nil
+	}
+	return contact
+}
+
+// checkRecipient returns an error if the recipient of the message is not a
+// contact bound to the channel.
+func checkRecipient(channel *models.Channel, urn string, recipient *models.Contact) error {
+	if recipient == nil || recipient.Channel != channel.ID {
+		return fmt.Errorf("recipient %s is not bound to channel %s", urn, channel.UUID)
+	}
+	return nil
+}
+
+// saveSentMessages records the ids returned by whatsapp for a sent message
+// with the sending channel, or the channel of its recipient when there is no
+// sending channel, so status callbacks can be routed back.
+func (c *CourierHandler) saveSentMessages(channel *models.Channel, recipient *models.Contact, urn string, resBody []byte) {
+	if urn == "" {
+		return
+	}
+	var res sendMessageResponse
+	if err := json.Unmarshal(resBody, &res); err != nil || len(res.Messages) == 0 {
+		return
+	}
+	var channelID primitive.ObjectID
+	switch {
+	case channel != nil:
+		channelID = channel.ID
+	case recipient != nil:
+		channelID = recipient.Channel
+	default:
+		return
+	}
+	for _, m := range res.Messages {
+		_, err := c.MessageService.CreateMessage(&models.Message{
+			MessageID: m.ID,
+			URN:       urn,
+			Channel:   channelID,
+		})
+		if err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+// reengagementMessage returns the re-engagement template message to the
+// contact, in the on-premises api format.
+func reengagementMessage(to string) ([]byte, error) {
+	return json.Marshal(map[string]interface{}{
+		"to":   to,
+		"type": "template",
+		"template": map[string]interface{}{
+			"namespace": reengagement.Namespace,
+			"name":      reengagement.Template,
+			"language": map[string]string{
+				"policy": "deterministic",
+				"code":   reengagement.Language,
+			},
+		},
+	})
+}
+
+// serviceWindowError returns the error of a free-form message to a contact out
+// of the customer service window, in the error format of the whatsapp api so
+// courier logs it with the message.
+func serviceWindowError(contact *models.Contact) map[string]interface{} {
+	return map[string]interface{}{
+		"errors": []map[string]interface{}{{
+			"code":  470,
+			"title": "Message failed to send because more than 24 hours have passed since the customer last replied to this number",
+			"details": fmt.Sprintf(
+				"the service window of %s closed at %s, only template messages can be sent until the contact sends a new message",
+				contact.URN, contact.ServiceWindowExpiresAt().Format(time.RFC3339),
+			),
+		}},
+	}
+}
+
+type sendMessageRequest struct {
+	To   string `json:"to"`
+	Type string `json:"type"`
+}
+
+// isTemplate reports whether the message is a template, including the hsm
+// messages of the on-premises API.
+func (r sendMessageRequest) isTemplate() bool {
+	return r.Type == "template" || r.Type == "hsm"
+}
+
+type sendMessageResponse struct {
+	Messages []struct {
+		ID string `json:"id"`
+	} `json:"messages"`
+}
@@ -2,16 +2,24 @@ package handlers
 
 import (
 	"bytes"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/metric"
 	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/services"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestHandleMessage(t *testing.T) {
@@ -29,8 +37,10 @@ func TestHandleMessage(t *testing.T) {
 		ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":{"id":"gBEGVYKZRIIyAgmiTgezkroUL2Q"}],"meta":{"api_status":"stable","version":"2.35.2"}}`))),
 		nil,
 	)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777"}).Return(nil, repositories.ErrNotFound)
 
-	ch := CourierHandler{mockWhatsappService}
+	ch := CourierHandler{WhatsappService: mockWhatsappService, ContactService: mockContactService}
 
 	router := chi.NewRouter()
 	router.Post("/v1/messages", ch.HandleSendMessage)
@@ -48,3 +58,242 @@ func TestHandleMessage(t *testing.T) {
 
 	assert.Equal(t, response.Code, 201)
 }
+
+func TestHandleMessageSavesSentMessage(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"to":"5582988887777","type":"text","text":{"body":"hello"}}`
+	payloadBytes := []byte(payload)
+
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockMessageService := mocks.NewMockMessageService(ctrl)
+	mockWhatsappService.EXPECT().SendMessage(payloadBytes).Return(
+		http.Header{
+			"content-type": {"application/json"},
+		},
+		ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[{"id":"gBEGVYKZRIIyAgmiTgezkroUL2Q"}],"meta":{"api_status":"stable","version":"2.35.2"}}`))),
+		nil,
+	)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: dummyContact.URN}).Return(dummyContact, nil)
+	mockMessageService.EXPECT().CreateMessage(&models.Message{
+		MessageID: "gBEGVYKZRIIyAgmiTgezkroUL2Q",
+		URN:       dummyContact.URN,
+		Channel:   dummyContact.Channel,
+	}).Return(&models.Message{}, nil)
+
+	ch := CourierHandler{
+		WhatsappService: mockWhatsappService,
+		ContactService:  mockContactService,
+		MessageService:  mockMessageService,
+	}
+
+	router := chi.NewRouter()
+	router.Post("/v1/messages", ch.HandleSendMessage)
+
+	request, _ := http.NewRequest(
+		http.MethodPost,
+		"/v1/messages",
+		strings.NewReader(payload),
+	)
+
+	response := httptest.NewRecorder()
+
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, 201, response.Code)
+}
+
+func TestHandleMessageSavesSendingChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"to":"5582988887777","type":"text","text":{"body":"hello"}}`
+	sendingChannel := &models.Channel{ID: primitive.NewObjectID(), UUID: DummyCh.UUID, Credential: "Zm9vYmFyYmF6"}
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockMessageService := mocks.NewMockMessageService(ctrl)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockChannelService.EXPECT().FindChannelByCredential(sendingChannel.Credential).Return(sendingChannel, nil)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777"}).Return(
+		&models.Contact{URN: "5582988887777", Channel: sendingChannel.ID}, nil,
+	)
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+	mockWhatsappService.EXPECT().SendMessage([]byte(payload)).Return(
+		http.Header{},
+		ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[{"id":"gBEGVYKZRIIyAgmiTgezkroUL2Q"}]}`))),
+		nil,
+	)
+	mockMessageService.EXPECT().CreateMessage(&models.Message{
+		MessageID: "gBEGVYKZRIIyAgmiTgezkroUL2Q",
+		URN:       "5582988887777",
+		Channel:   sendingChannel.ID,
+	}).Return(&models.Message{}, nil)
+
+	ch := CourierHandler{
+		WhatsappService: mockWhatsappService,
+		ContactService:  mockContactService,
+		MessageService:  mockMessageService,
+		ChannelService:  mockChannelService,
+		Metrics:         metricService,
+	}
+
+	router := chi.NewRouter()
+	router.Use(ChannelAuth(mockChannelService))
+	router.Post("/v1/messages", ch.HandleSendMessage)
+
+	request, _ := http.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(payload))
+	request.Header.Set("Authorization", "Bearer "+sendingChannel.Credential)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+
+	assert.Equal(t, 201, response.Code)
+}
+
+func TestHandleMessageToContactOfAnotherChannel(t *testing.T) {
+	payload := `{"to":"5582988887777","type":"text","text":{"body":"hello"}}`
+	sendingChannel := &models.Channel{ID: primitive.NewObjectID(), UUID: DummyCh.UUID, Credential: "Zm9vYmFyYmF6"}
+	otherChannel := primitive.NewObjectID()
+
+	for _, tc := range []struct {
+		Scoping string
+		Code    int
+	}{
+		{"reject", 403},
+		{"warn", 201},
+	} {
+		t.Run(tc.Scoping, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			defer func(scoping string) { outboundScoping = scoping }(outboundScoping)
+			outboundScoping = tc.Scoping
+
+			mockChannelService := mocks.NewMockChannelService(ctrl)
+			mockContactService := mocks.NewMockContactService(ctrl)
+			mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+			mockMessageService := mocks.NewMockMessageService(ctrl)
+			metricService, err := metric.NewPrometheusService()
+			assert.NoError(t, err)
+
+			mockChannelService.EXPECT().FindChannelByCredential(sendingChannel.Credential).Return(sendingChannel, nil)
+			mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777"}).Return(
+				&models.Contact{URN: "5582988887777", Channel: otherChannel}, nil,
+			)
+			if tc.Code == 201 {
+				mockWhatsappService.EXPECT().SendMessage([]byte(payload)).Return(
+					http.Header{},
+					ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[]}`))),
+					nil,
+				)
+			}
+
+			ch := CourierHandler{
+				WhatsappService: mockWhatsappService,
+				ContactService:  mockContactService,
+				MessageService:  mockMessageService,
+				ChannelService:  mockChannelService,
+				Metrics:         metricService,
+			}
+			router := chi.NewRouter()
+			router.Use(ChannelAuth(mockChannelService))
+			router.Post("/v1/messages", ch.HandleSendMessage)
+
+			request, _ := http.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(payload))
+			request.Header.Set("Authorization", "Bearer "+sendingChannel.Credential)
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.Code, response.Code)
+		})
+	}
+}
+
+func TestHandleMessageRateLimited(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	payload := `{"to":"5582988887777","type":"text","text":{"body":"hello"}}`
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockWhatsappService.EXPECT().SendMessage([]byte(payload)).Return(
+		http.Header{},
+		ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[]}`))),
+		nil,
+	).Times(1)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().FindContact(gomock.Any()).Return(nil, repositories.ErrNotFound).AnyTimes()
+
+	ch := CourierHandler{
+		WhatsappService: services.NewRateLimitedWhatsappService(mockWhatsappService, services.NewRateLimiter(1, 1, 0, 0)),
+		ContactService:  mockContactService,
+	}
+	router := chi.NewRouter()
+	router.Post("/v1/messages", ch.HandleSendMessage)
+
+	var response *httptest.ResponseRecorder
+	for _, code := range []int{http.StatusCreated, http.StatusTooManyRequests} {
+		request, _ := http.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(payload))
+		response = httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		assert.Equal(t, code, response.Code)
+	}
+	// the retry after of the next token, rounded up to seconds
+	assert.Equal(t, "1", response.Header().Get("Retry-After"))
+}
+
+func TestHandleMessageOutOfServiceWindow(t *testing.T) {
+	text := `{"to":"5582988887777","type":"text","text":{"body":"hello"}}`
+	template := `{"to":"5582988887777","type":"template","template":{"namespace":"weni","name":"hello","language":{"policy":"deterministic","code":"en"}}}`
+	hsm := `{"to":"5582988887777","type":"hsm","hsm":{"namespace":"weni","element_name":"hello","language":{"policy":"deterministic","code":"en"},"localizable_params":[]}}`
+	reengagementTemplate := `{"to":"5582988887777","type":"template","template":{"namespace":"weni","name":"reengagement","language":{"policy":"deterministic","code":"pt_BR"}}}`
+
+	tcs := []struct {
+		Label    string
+		Template string
+		Payload  string
+		Sent     string
+		Code     int
+	}{
+		{"free-form message", "", text, "", http.StatusUnprocessableEntity},
+		{"template message", "", template, template, http.StatusCreated},
+		{"hsm message", "", hsm, hsm, http.StatusCreated},
+		{"hsm message with re-engagement template", "reengagement", hsm, hsm, http.StatusCreated},
+		{"re-engagement template", "reengagement", text, reengagementTemplate, http.StatusCreated},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Label, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+			defer func(r config.Reengagement) { reengagement = r }(reengagement)
+			reengagement = config.Reengagement{Template: tc.Template, Language: "pt_BR", Namespace: "weni"}
+
+			mockContactService := mocks.NewMockContactService(ctrl)
+			mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777"}).Return(&models.Contact{
+				URN:           "5582988887777",
+				LastInboundAt: time.Now().Add(-25 * time.Hour),
+			}, nil)
+			mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+			if tc.Sent != "" {
+				mockWhatsappService.EXPECT().SendMessage(gomock.Any()).DoAndReturn(func(body []byte) (http.Header, io.ReadCloser, error) {
+					assert.JSONEq(t, tc.Sent, string(body))
+					return http.Header{}, ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[]}`))), nil
+				})
+			}
+
+			ch := CourierHandler{WhatsappService: mockWhatsappService, ContactService: mockContactService}
+			router := chi.NewRouter()
+			router.Post("/v1/messages", ch.HandleSendMessage)
+
+			request, _ := http.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(tc.Payload))
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+
+			assert.Equal(t, tc.Code, response.Code)
+			if tc.Code == http.StatusUnprocessableEntity {
+				assert.Contains(t, response.Body.String(), `"code":470`)
+			}
+		})
+	}
+}
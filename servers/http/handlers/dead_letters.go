@@ -0,0 +1,77 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/go-chi/chi"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/services"
+)
+
+const (
+	defaultPageLimit = 20
+	maxPageLimit     = 100
+)
+
+type DeadLetterHandler struct {
+	CourierService services.CourierService
+}
+
+func (h *DeadLetterHandler) HandleListDeadLetters(w http.ResponseWriter, r *http.Request) {
+	page, limit, err := paginationParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	forwards, err := h.CourierService.ListDeadLetters(page, limit)
+	if err != nil {
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, forwards)
+}
+
+func (h *DeadLetterHandler) HandleReplayDeadLetter(w http.ResponseWriter, r *http.Request) {
+	id := chi.URLParam(r, "id")
+	forward, err := h.CourierService.ReplayDeadLetter(id)
+	if err != nil {
+		if errors.Is(err, repositories.ErrNotFound) {
+			http.Error(w, "dead letter not found", http.StatusNotFound)
+			return
+		}
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, forward)
+}
+
+// paginationParams reads the page and limit query params, defaulting to the
+// first page of defaultPageLimit items.
+func paginationParams(r *http.Request) (int64, int64, error) {
+	page, limit := int64(1), int64(defaultPageLimit)
+	if p := r.URL.Query().Get("page"); p != "" {
+		v, err := strconv.ParseInt(p, 10, 64)
+		if err != nil || v < 1 {
+			return 0, 0, errInvalidParam("page")
+		}
+		page = v
+	}
+	if l := r.URL.Query().Get("limit"); l != "" {
+		v, err := strconv.ParseInt(l, 10, 64)
+		if err != nil || v < 1 || v > maxPageLimit {
+			return 0, 0, errInvalidParam("limit")
+		}
+		limit = v
+	}
+	return page, limit, nil
+}
+
+func errInvalidParam(name string) error {
+	return fmt.Errorf("invalid %s param", name)
+}
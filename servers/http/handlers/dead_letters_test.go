@@ -0,0 +1,86 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var dummyForward = &models.Forward{
+	ID:          primitive.NewObjectID(),
+	ChannelUUID: "21ee95f6-3776-4b1e-aabc-742eb5dc9170",
+	Payload:     `{"messages":[]}`,
+	Attempts:    10,
+	LastError:   "courier responded with status 503",
+}
+
+func TestHandleListDeadLetters(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCourierService := mocks.NewMockCourierService(ctrl)
+	mockCourierService.EXPECT().ListDeadLetters(int64(2), int64(10)).Return([]*models.Forward{dummyForward}, nil)
+
+	dh := DeadLetterHandler{mockCourierService}
+	router := chi.NewRouter()
+	router.Get("/admin/dead-letters", dh.HandleListDeadLetters)
+
+	request, err := http.NewRequest(http.MethodGet, "/admin/dead-letters?page=2&limit=10", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+
+	var forwards []*models.Forward
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&forwards))
+	assert.Len(t, forwards, 1)
+	assert.Equal(t, dummyForward.ID, forwards[0].ID)
+
+	request, err = http.NewRequest(http.MethodGet, "/admin/dead-letters?limit=1000", nil)
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code)
+}
+
+func TestHandleReplayDeadLetter(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockCourierService := mocks.NewMockCourierService(ctrl)
+	mockCourierService.EXPECT().ReplayDeadLetter(dummyForward.ID.Hex()).Return(dummyForward, nil)
+	mockCourierService.EXPECT().ReplayDeadLetter("unknown").Return(nil, repositories.ErrNotFound)
+	mockCourierService.EXPECT().ReplayDeadLetter("5f8a0b0c0d0e0f1011121314").Return(nil, errors.New("unexpected database error: connection refused"))
+
+	dh := DeadLetterHandler{mockCourierService}
+	router := chi.NewRouter()
+	router.Post("/admin/dead-letters/{id}/replay", dh.HandleReplayDeadLetter)
+
+	request, err := http.NewRequest(http.MethodPost, "/admin/dead-letters/"+dummyForward.ID.Hex()+"/replay", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 202, response.Code)
+
+	request, err = http.NewRequest(http.MethodPost, "/admin/dead-letters/unknown/replay", nil)
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 404, response.Code)
+
+	request, err = http.NewRequest(http.MethodPost, "/admin/dead-letters/5f8a0b0c0d0e0f1011121314/replay", nil)
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 500, response.Code)
+}
@@ -1,21 +1,17 @@
 package handlers
 
 import (
-	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"net/http"
-	"strings"
 
-	"github.com/Nerzal/gocloak/v11"
-	"github.com/weni/whatsapp-router/config"
+	"github.com/go-chi/chi"
+	"github.com/weni/whatsapp-router/logger"
 	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
 	"github.com/weni/whatsapp-router/services"
-	"github.com/weni/whatsapp-router/utils"
 )
 
-var kkClient gocloak.GoCloak
-
 type IntegrationsHandler struct {
 	ChannelService services.ChannelService
 }
@@ -31,38 +27,114 @@ func (h *IntegrationsHandler) HandleCreateChannel(w http.ResponseWriter, r *http
 		http.Error(w, "channel uuid could not be empty", http.StatusBadRequest)
 		return
 	}
-	ch.Token = utils.GenToken()
-	_, err = h.ChannelService.CreateChannelDefault(ch)
+	ch, err = h.ChannelService.CreateChannelDefault(ch)
+	if err != nil {
+		channelError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusCreated, channelCredentialResponse{Token: ch.Token, Credential: ch.Credential})
+}
+
+func (h *IntegrationsHandler) HandleListChannels(w http.ResponseWriter, r *http.Request) {
+	page, limit, err := paginationParams(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	channels, err := h.ChannelService.ListChannelsDefault(page, limit)
 	if err != nil {
+		logger.Error(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	w.WriteHeader(http.StatusCreated)
-	w.Write([]byte(fmt.Sprintf(`{"token":"%s"}`, ch.Token)))
+	writeJSON(w, http.StatusOK, channels)
 }
 
-func KeycloackAuth(next http.HandlerFunc) http.HandlerFunc {
-	if kkClient == nil {
-		kkClient = NewKeycloakClient()
+func (h *IntegrationsHandler) HandleGetChannel(w http.ResponseWriter, r *http.Request) {
+	ch, err := h.ChannelService.FindChannel(&models.Channel{UUID: chi.URLParam(r, "uuid")})
+	if err != nil {
+		channelError(w, err)
+		return
 	}
-	return func(w http.ResponseWriter, r *http.Request) {
-		authorization := r.Header.Get("Authorization")
-		authSplit := strings.Split(authorization, " ")
-		var token string
-		token = authSplit[0]
-		if len(authSplit) > 1 {
-			token = authSplit[1]
-		}
+	writeJSON(w, http.StatusOK, ch)
+}
 
-		ctx := context.Background()
-		_, err := kkClient.GetUserInfo(ctx, token, config.GetConfig().OIDC.Realm)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusUnauthorized)
-			return
-		}
-		next.ServeHTTP(w, r)
+func (h *IntegrationsHandler) HandleUpdateChannel(w http.ResponseWriter, r *http.Request) {
+	ch := &models.Channel{}
+	if err := json.NewDecoder(r.Body).Decode(ch); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if services.IsEmptyChannelUpdate(ch) {
+		http.Error(w, services.ErrEmptyChannelUpdate.Error(), http.StatusBadRequest)
+		return
+	}
+	ch.UUID = chi.URLParam(r, "uuid")
+	updated, err := h.ChannelService.UpdateChannelDefault(ch)
+	if err != nil {
+		channelError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, updated)
+}
+
+// HandleDeleteChannel deletes a channel, reassigning its contacts to the
+// channel given by the reassign_to query param or unbinding them otherwise.
+func (h *IntegrationsHandler) HandleDeleteChannel(w http.ResponseWriter, r *http.Request) {
+	uuid := chi.URLParam(r, "uuid")
+	reassignTo := r.URL.Query().Get("reassign_to")
+	if reassignTo == uuid {
+		http.Error(w, "channel could not be reassigned to itself", http.StatusBadRequest)
+		return
+	}
+	if err := h.ChannelService.DeleteChannelDefault(uuid, reassignTo); err != nil {
+		channelError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *IntegrationsHandler) HandleRotateChannelToken(w http.ResponseWriter, r *http.Request) {
+	ch, err := h.ChannelService.RotateChannelTokenDefault(chi.URLParam(r, "uuid"))
+	if err != nil {
+		channelError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, ch)
+}
+
+func (h *IntegrationsHandler) HandleRotateChannelCredential(w http.ResponseWriter, r *http.Request) {
+	ch, err := h.ChannelService.RotateChannelCredentialDefault(chi.URLParam(r, "uuid"))
+	if err != nil {
+		channelError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, channelCredentialResponse{UUID: ch.UUID, Token: ch.Token, Credential: ch.Credential})
+}
+
+// channelCredentialResponse is the response of the endpoints that generate
+// the credential of a channel, the only ones that return it.
+type channelCredentialResponse struct {
+	UUID       string `json:"uuid,omitempty"`
+	Token      string `json:"token"`
+	Credential string `json:"credential,omitempty"`
+}
+
+func channelError(w http.ResponseWriter, err error) {
+	if errors.Is(err, repositories.ErrNotFound) {
+		http.Error(w, "channel not found", http.StatusNotFound)
+		return
+	}
+	if errors.Is(err, services.ErrAccountNotFound) || errors.Is(err, services.ErrAccountMismatch) {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
 	}
+	logger.Error(err.Error())
+	http.Error(w, err.Error(), http.StatusInternalServerError)
 }
 
-func NewKeycloakClient() gocloak.GoCloak {
-	return gocloak.NewClient(config.GetConfig().OIDC.Host)
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
 }
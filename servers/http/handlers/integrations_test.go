@@ -22,17 +22,25 @@ import (
 	"github.com/Nerzal/gocloak/v11"
 	"github.com/go-chi/chi"
 	"github.com/go-resty/resty/v2"
+	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/weni/whatsapp-router/config"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
 	"github.com/weni/whatsapp-router/models"
-	"github.com/weni/whatsapp-router/servers/grpc/pb"
+	"github.com/weni/whatsapp-router/repositories"
 )
 
 func TestHandleCreateChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
 	dummyPayload := `{"uuid":"425b41f0-c554-4943-989c-5f88561a0cf5","name":"test-channel"}`
 
-	ih := IntegrationsHandler{mockChannelService{}}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().CreateChannelDefault(gomock.Any()).Return(DummyCh, nil)
+
+	ih := IntegrationsHandler{mockChannelService}
 	router := chi.NewRouter()
 	router.Post("/v1/channels", ih.HandleCreateChannel)
 	request, err := http.NewRequest(
@@ -52,43 +60,232 @@ func TestHandleCreateChannel(t *testing.T) {
 }
 
 var DummyCh = &models.Channel{
-	UUID: "425b41f0-c554-4943-989c-5f88561a0cf5",
-	Name: "test-channel",
+	UUID:  "425b41f0-c554-4943-989c-5f88561a0cf5",
+	Name:  "test-channel",
+	Token: "weni-demo-4dd9e1b3a6",
 }
 
-type mockChannelService struct {
+func TestHandleListChannels(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().ListChannelsDefault(int64(1), int64(20)).Return([]*models.Channel{DummyCh}, nil)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Get("/integrations/channel", ih.HandleListChannels)
+	request, err := http.NewRequest(http.MethodGet, "/integrations/channel", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+
+	var channels []*models.Channel
+	assert.NoError(t, json.NewDecoder(response.Body).Decode(&channels))
+	assert.Equal(t, []*models.Channel{DummyCh}, channels)
 }
 
-func (cs mockChannelService) CreateChannelDefault(*models.Channel) (*models.Channel, error) {
-	return DummyCh, nil
+func TestHandleGetChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: DummyCh.UUID}).Return(DummyCh, nil)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: "unknown"}).Return(nil, repositories.ErrNotFound)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Get("/integrations/channel/{uuid}", ih.HandleGetChannel)
+
+	request, err := http.NewRequest(http.MethodGet, "/integrations/channel/"+DummyCh.UUID, nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+
+	request, err = http.NewRequest(http.MethodGet, "/integrations/channel/unknown", nil)
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 404, response.Code)
 }
 
-func (cs mockChannelService) CreateChannel(ctx context.Context, channel *pb.ChannelRequest) (*pb.ChannelResponse, error) {
-	return nil, nil
+func TestHandleUpdateChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	updated := &models.Channel{UUID: DummyCh.UUID, Name: "renamed"}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().UpdateChannelDefault(updated).Return(updated, nil)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Patch("/integrations/channel/{uuid}", ih.HandleUpdateChannel)
+
+	request, err := http.NewRequest(http.MethodPatch, "/integrations/channel/"+DummyCh.UUID, strings.NewReader(`{"name":"renamed"}`))
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+
+	welcome := &models.Channel{
+		UUID:            DummyCh.UUID,
+		WelcomeMessage:  "Welcome",
+		WelcomeMessages: map[string]string{"pt": "Bem vindo"},
+	}
+	mockChannelService.EXPECT().UpdateChannelDefault(welcome).Return(welcome, nil)
+	request, err = http.NewRequest(http.MethodPatch, "/integrations/channel/"+DummyCh.UUID, strings.NewReader(`{"welcome_message":"Welcome","welcome_messages":{"pt":"Bem vindo"}}`))
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+	assert.Contains(t, response.Body.String(), `"welcome_messages":{"pt":"Bem vindo"}`)
+
+	expiration := &models.Channel{UUID: DummyCh.UUID, BindingTTL: 604800, InactivityTimeout: -1}
+	mockChannelService.EXPECT().UpdateChannelDefault(expiration).Return(&models.Channel{UUID: DummyCh.UUID, BindingTTL: 604800}, nil)
+	request, err = http.NewRequest(http.MethodPatch, "/integrations/channel/"+DummyCh.UUID, strings.NewReader(`{"binding_ttl":604800,"inactivity_timeout":-1}`))
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+	assert.Contains(t, response.Body.String(), `"binding_ttl":604800`)
+
+	request, err = http.NewRequest(http.MethodPatch, "/integrations/channel/"+DummyCh.UUID, strings.NewReader(`{"name":""}`))
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 400, response.Code)
 }
 
-func (cs mockChannelService) FindChannel(channel *models.Channel) (*models.Channel, error) {
-	return nil, nil
+func TestHandleDeleteChannel(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	otherUUID := "a1b2c3d4-0000-4000-8000-000000000000"
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().DeleteChannelDefault(DummyCh.UUID, otherUUID).Return(nil)
+	mockChannelService.EXPECT().DeleteChannelDefault("unknown", "").Return(repositories.ErrNotFound)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Delete("/integrations/channel/{uuid}", ih.HandleDeleteChannel)
+
+	request, err := http.NewRequest(http.MethodDelete, "/integrations/channel/"+DummyCh.UUID+"?reassign_to="+otherUUID, nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 204, response.Code)
+
+	request, err = http.NewRequest(http.MethodDelete, "/integrations/channel/unknown", nil)
+	assert.NoError(t, err)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 404, response.Code)
 }
 
-func (cs mockChannelService) FindChannelById(id string) (*models.Channel, error) {
-	return nil, nil
+func TestHandleRotateChannelToken(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rotated := &models.Channel{UUID: DummyCh.UUID, Name: DummyCh.Name, Token: "weni-demo-0123456789"}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().RotateChannelTokenDefault(DummyCh.UUID).Return(rotated, nil)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Post("/integrations/channel/{uuid}/rotate-token", ih.HandleRotateChannelToken)
+
+	request, err := http.NewRequest(http.MethodPost, "/integrations/channel/"+DummyCh.UUID+"/rotate-token", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+	assert.True(t, strings.Contains(response.Body.String(), rotated.Token))
 }
 
-func (cs mockChannelService) FindChannelByToken(token string) (*models.Channel, error) {
-	return nil, nil
+func TestHandleRotateChannelCredential(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	rotated := &models.Channel{UUID: DummyCh.UUID, Name: DummyCh.Name, Token: DummyCh.Token, Credential: "Zm9vYmFyYmF6"}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().RotateChannelCredentialDefault(DummyCh.UUID).Return(rotated, nil)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Post("/integrations/channel/{uuid}/rotate-credential", ih.HandleRotateChannelCredential)
+
+	request, err := http.NewRequest(http.MethodPost, "/integrations/channel/"+DummyCh.UUID+"/rotate-credential", nil)
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 200, response.Code)
+	assert.True(t, strings.Contains(response.Body.String(), rotated.Credential))
+}
+
+func TestChannelCredentialNotReturned(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	withCredential := &models.Channel{UUID: DummyCh.UUID, Name: DummyCh.Name, Token: DummyCh.Token, Credential: "Zm9vYmFyYmF6"}
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().ListChannelsDefault(int64(1), int64(20)).Return([]*models.Channel{withCredential}, nil)
+	mockChannelService.EXPECT().FindChannel(&models.Channel{UUID: DummyCh.UUID}).Return(withCredential, nil)
+	mockChannelService.EXPECT().UpdateChannelDefault(gomock.Any()).Return(withCredential, nil)
+	mockChannelService.EXPECT().RotateChannelTokenDefault(DummyCh.UUID).Return(withCredential, nil)
+	// the credential is generated, never taken from the payload
+	mockChannelService.EXPECT().CreateChannelDefault(&models.Channel{UUID: DummyCh.UUID}).Return(withCredential, nil)
+
+	ih := IntegrationsHandler{mockChannelService}
+	router := chi.NewRouter()
+	router.Post("/integrations/channel", ih.HandleCreateChannel)
+	router.Get("/integrations/channel", ih.HandleListChannels)
+	router.Get("/integrations/channel/{uuid}", ih.HandleGetChannel)
+	router.Patch("/integrations/channel/{uuid}", ih.HandleUpdateChannel)
+	router.Post("/integrations/channel/{uuid}/rotate-token", ih.HandleRotateChannelToken)
+
+	requests := []struct {
+		Method string
+		Path   string
+		Body   string
+	}{
+		{http.MethodGet, "/integrations/channel", ""},
+		{http.MethodGet, "/integrations/channel/" + DummyCh.UUID, ""},
+		{http.MethodPatch, "/integrations/channel/" + DummyCh.UUID, `{"name":"renamed"}`},
+		{http.MethodPost, "/integrations/channel/" + DummyCh.UUID + "/rotate-token", ""},
+	}
+	for _, tc := range requests {
+		request, err := http.NewRequest(tc.Method, tc.Path, strings.NewReader(tc.Body))
+		assert.NoError(t, err)
+		response := httptest.NewRecorder()
+		router.ServeHTTP(response, request)
+		assert.Equal(t, 200, response.Code)
+		assert.NotContains(t, response.Body.String(), "credential", tc.Method+" "+tc.Path)
+		assert.NotContains(t, response.Body.String(), withCredential.Credential, tc.Method+" "+tc.Path)
+	}
+
+	request, err := http.NewRequest(http.MethodPost, "/integrations/channel", strings.NewReader(`{"uuid":"`+DummyCh.UUID+`","credential":"chosen"}`))
+	assert.NoError(t, err)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, 201, response.Code)
+	assert.JSONEq(t, `{"token":"`+DummyCh.Token+`","credential":"Zm9vYmFyYmF6"}`, response.Body.String())
 }
 
 func TestKeycloakAuth(t *testing.T) {
 	cfg := GetConfig(t)
-	kkClient = NewClientWithDebug(t)
+	kkClient := NewClientWithDebug(t)
 	assert.NotNil(t, kkClient)
 
 	SetUpTestUser(t, kkClient)
 
 	token := GetUserToken(t, kkClient)
 
+	config.GetConfig().OIDC.Host = cfg.HostName
 	config.GetConfig().OIDC.Realm = cfg.GoCloak.Realm
+	config.GetConfig().OIDC.Audience = "account"
+	tokenValidator = nil
 
 	log.Println("GGwp")
 
@@ -0,0 +1,271 @@
+package handlers
+
+import (
+	"context"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/utils"
+)
+
+// Roles of the keycloak users allowed to call the integrations and admin
+// endpoints.
+const (
+	RoleChannelRead     = "channel:read"
+	RoleChannelWrite    = "channel:write"
+	RoleAccountRead     = "account:read"
+	RoleAccountWrite    = "account:write"
+	RoleContactRead     = "contact:read"
+	RoleContactWrite    = "contact:write"
+	RoleTemplateRead    = "template:read"
+	RoleTemplateWrite   = "template:write"
+	RoleDeadLetterRead  = "dead-letter:read"
+	RoleDeadLetterWrite = "dead-letter:write"
+)
+
+// jwksMinRefresh is the minimum interval between fetches of the keys, so
+// tokens signed with unknown keys do not flood keycloak.
+const jwksMinRefresh = 30 * time.Second
+
+var tokenValidator *TokenValidator
+
+type identityContextKey struct{}
+
+// Identity is the keycloak user authenticated by an access token.
+type Identity struct {
+	Subject  string
+	Username string
+	Email    string
+	Roles    []string
+}
+
+func (i *Identity) HasRole(role string) bool {
+	for _, r := range i.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+func (i *Identity) String() string {
+	if i.Username != "" {
+		return i.Username
+	}
+	return i.Subject
+}
+
+// IdentityFromContext returns the identity authenticated by KeycloackAuth, or
+// nil if the request was not authenticated.
+func IdentityFromContext(ctx context.Context) *Identity {
+	identity, _ := ctx.Value(identityContextKey{}).(*Identity)
+	return identity
+}
+
+// KeycloackAuth authenticates the request with the bearer keycloak access
+// token, validated with the keys of the realm, and requires the user to have
+// all the given roles. The identity of the user is put in the request context
+// and the requests changing data are logged with it.
+func KeycloackAuth(next http.HandlerFunc, roles ...string) http.HandlerFunc {
+	if tokenValidator == nil {
+		tokenValidator = NewTokenValidator(config.GetConfig().OIDC)
+	}
+	validator := tokenValidator
+	return func(w http.ResponseWriter, r *http.Request) {
+		token, ok := bearerToken(r)
+		if !ok {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "missing bearer token", http.StatusUnauthorized)
+			return
+		}
+		identity, err := validator.Validate(token)
+		if err != nil {
+			logger.Debug(fmt.Sprintf("invalid access token: %s", err))
+			w.Header().Set("WWW-Authenticate", `Bearer error="invalid_token"`)
+			http.Error(w, "invalid access token", http.StatusUnauthorized)
+			return
+		}
+		for _, role := range roles {
+			if !identity.HasRole(role) {
+				http.Error(w, fmt.Sprintf("role %s required", role), http.StatusForbidden)
+				return
+			}
+		}
+		if r.Method != http.MethodGet {
+			logger.Info(fmt.Sprintf("audit: %s %s by %s (%s)", r.Method, r.URL.Path, identity, identity.Subject))
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), identityContextKey{}, identity)))
+	}
+}
+
+// bearerToken returns the token of the Authorization header with the Bearer
+// scheme.
+func bearerToken(r *http.Request) (string, bool) {
+	parts := strings.SplitN(r.Header.Get("Authorization"), " ", 2)
+	if len(parts) != 2 || !strings.EqualFold(parts[0], "Bearer") {
+		return "", false
+	}
+	token := strings.TrimSpace(parts[1])
+	return token, token != ""
+}
+
+// TokenValidator validates RS256 keycloak access tokens locally, checking
+// their signature, expiration, issuer and audience.
+type TokenValidator struct {
+	jwks     *JWKS
+	issuer   string
+	audience string
+}
+
+func NewTokenValidator(conf config.OIDC) *TokenValidator {
+	issuer := conf.IssuerURL()
+	return &TokenValidator{
+		jwks:     NewJWKS(issuer+"/protocol/openid-connect/certs", conf.JWKSCacheTTL),
+		issuer:   issuer,
+		audience: conf.TokenAudience(),
+	}
+}
+
+type keycloakClaims struct {
+	jwt.RegisteredClaims
+	PreferredUsername string `json:"preferred_username"`
+	Email             string `json:"email"`
+	RealmAccess       struct {
+		Roles []string `json:"roles"`
+	} `json:"realm_access"`
+	ResourceAccess map[string]struct {
+		Roles []string `json:"roles"`
+	} `json:"resource_access"`
+}
+
+// Validate returns the identity of the user of the token, with its realm
+// roles and the roles of the audience client.
+func (v *TokenValidator) Validate(token string) (*Identity, error) {
+	var claims keycloakClaims
+	_, err := jwt.ParseWithClaims(token, &claims, func(t *jwt.Token) (interface{}, error) {
+		kid, _ := t.Header["kid"].(string)
+		return v.jwks.Key(kid)
+	}, jwt.WithValidMethods([]string{"RS256"}))
+	if err != nil {
+		return nil, err
+	}
+	if claims.ExpiresAt == nil {
+		return nil, errors.New("token without expiration")
+	}
+	if !claims.VerifyIssuer(v.issuer, true) {
+		return nil, fmt.Errorf("token issued by %q", claims.Issuer)
+	}
+	if !claims.VerifyAudience(v.audience, true) {
+		return nil, fmt.Errorf("token not issued to %q", v.audience)
+	}
+
+	identity := &Identity{
+		Subject:  claims.Subject,
+		Username: claims.PreferredUsername,
+		Email:    claims.Email,
+		Roles:    append(claims.RealmAccess.Roles, claims.ResourceAccess[v.audience].Roles...),
+	}
+	return identity, nil
+}
+
+// JWKS caches the rsa keys of a json web key set, fetching them again when
+// they are older than the ttl or when a token is signed with an unknown key.
+type JWKS struct {
+	url string
+	ttl time.Duration
+
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+func NewJWKS(url string, ttl time.Duration) *JWKS {
+	return &JWKS{url: url, ttl: ttl}
+}
+
+// Key returns the key with the given id.
+func (j *JWKS) Key(kid string) (*rsa.PublicKey, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	key, ok := j.keys[kid]
+	age := time.Since(j.fetchedAt)
+	if (ok && age < j.ttl) || (!ok && age < jwksMinRefresh) {
+		if !ok {
+			return nil, fmt.Errorf("unknown key %q", kid)
+		}
+		return key, nil
+	}
+
+	keys, err := fetchJWKS(j.url)
+	if err != nil {
+		// the cached keys are used while keycloak is unavailable
+		logger.Error(fmt.Sprintf("unable to fetch keys: %s", err))
+		if ok {
+			return key, nil
+		}
+		return nil, err
+	}
+	j.keys = keys
+	j.fetchedAt = time.Now()
+	if key, ok := j.keys[kid]; ok {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown key %q", kid)
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+}
+
+// fetchJWKS returns the rsa signing keys of the key set at the url by id.
+func fetchJWKS(url string) (map[string]*rsa.PublicKey, error) {
+	res, err := utils.GetHTTPClient().Get(url)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("keys responded with %s", res.Status)
+	}
+	var set struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&set); err != nil {
+		return nil, err
+	}
+
+	keys := map[string]*rsa.PublicKey{}
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" || (k.Use != "" && k.Use != "sig") {
+			continue
+		}
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, fmt.Errorf("invalid modulus of key %q: %w", k.Kid, err)
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, fmt.Errorf("invalid exponent of key %q: %w", k.Kid, err)
+		}
+		keys[k.Kid] = &rsa.PublicKey{
+			N: new(big.Int).SetBytes(n),
+			E: int(new(big.Int).SetBytes(e).Int64()),
+		}
+	}
+	return keys, nil
+}
@@ -0,0 +1,154 @@
+package handlers
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/go-chi/chi"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/weni/whatsapp-router/config"
+)
+
+const testIssuer = "/auth/realms/weni"
+
+// newTestRealm serves the keys of a realm, returning its issuer, a function
+// signing tokens with its key and the number of times the keys were fetched.
+func newTestRealm(t *testing.T) (string, func(jwt.Claims) string, *int32) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	var fetches int32
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, testIssuer+"/protocol/openid-connect/certs", r.URL.Path)
+		atomic.AddInt32(&fetches, 1)
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"keys": []map[string]string{{
+				"kid": "test-key",
+				"kty": "RSA",
+				"alg": "RS256",
+				"use": "sig",
+				"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+				"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+			}},
+		})
+	}))
+	t.Cleanup(server.Close)
+
+	sign := func(claims jwt.Claims) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "test-key"
+		signed, err := token.SignedString(key)
+		require.NoError(t, err)
+		return signed
+	}
+	return server.URL + testIssuer, sign, &fetches
+}
+
+func testClaims(issuer string, roles ...string) *keycloakClaims {
+	claims := &keycloakClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   "f3c5e0b2-6b8a-4d3c-9e1f-2a4b6c8d0e1f",
+			Issuer:    issuer,
+			Audience:  jwt.ClaimStrings{"whatsapp-router"},
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour)),
+		},
+		PreferredUsername: "admin",
+	}
+	claims.RealmAccess.Roles = roles
+	return claims
+}
+
+func TestKeycloackAuthLocalValidation(t *testing.T) {
+	issuer, sign, fetches := newTestRealm(t)
+	tokenValidator = NewTokenValidator(config.OIDC{
+		Issuer:       issuer,
+		Audience:     "whatsapp-router",
+		JWKSCacheTTL: time.Hour,
+	})
+	defer func() { tokenValidator = nil }()
+
+	expired := testClaims(issuer, RoleChannelWrite)
+	expired.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	otherAudience := testClaims(issuer, RoleChannelWrite)
+	otherAudience.Audience = jwt.ClaimStrings{"account"}
+	clientRole := testClaims(issuer)
+	clientRole.ResourceAccess = map[string]struct {
+		Roles []string `json:"roles"`
+	}{"whatsapp-router": {Roles: []string{RoleChannelWrite}}}
+
+	tcs := []struct {
+		Label         string
+		Authorization string
+		Code          int
+	}{
+		{"realm role", "Bearer " + sign(testClaims(issuer, RoleChannelWrite)), 200},
+		{"client role", "bearer " + sign(clientRole), 200},
+		{"missing role", "Bearer " + sign(testClaims(issuer, RoleChannelRead)), 403},
+		{"expired", "Bearer " + sign(expired), 401},
+		{"other issuer", "Bearer " + sign(testClaims("http://localhost/auth/realms/other", RoleChannelWrite)), 401},
+		{"other audience", "Bearer " + sign(otherAudience), 401},
+		{"not signed", "Bearer " + sign(testClaims(issuer, RoleChannelWrite))[:20], 401},
+		{"no bearer scheme", sign(testClaims(issuer, RoleChannelWrite)), 401},
+		{"no authorization", "", 401},
+	}
+
+	router := chi.NewRouter()
+	router.Post("/integrations/channel", KeycloackAuth(func(w http.ResponseWriter, r *http.Request) {
+		identity := IdentityFromContext(r.Context())
+		assert.Equal(t, "admin", identity.Username)
+		w.WriteHeader(http.StatusOK)
+	}, RoleChannelWrite))
+
+	for _, tc := range tcs {
+		t.Run(tc.Label, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/integrations/channel", nil)
+			if tc.Authorization != "" {
+				request.Header.Set("Authorization", tc.Authorization)
+			}
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+			assert.Equal(t, tc.Code, response.Code)
+		})
+	}
+	// keys are cached
+	assert.Equal(t, int32(1), atomic.LoadInt32(fetches))
+}
+
+func TestTokenValidatorDefaultAudience(t *testing.T) {
+	issuer, sign, _ := newTestRealm(t)
+	// without OIDC_AUDIENCE the tokens must be issued to the client of the router
+	validator := NewTokenValidator(config.OIDC{
+		Issuer:       issuer,
+		ClientID:     "whatsapp-router",
+		JWKSCacheTTL: time.Hour,
+	})
+
+	clientRole := testClaims(issuer)
+	clientRole.ResourceAccess = map[string]struct {
+		Roles []string `json:"roles"`
+	}{"whatsapp-router": {Roles: []string{RoleChannelWrite}}, "account": {Roles: []string{RoleChannelRead}}}
+	identity, err := validator.Validate(sign(clientRole))
+	require.NoError(t, err)
+	assert.Equal(t, []string{RoleChannelWrite}, identity.Roles)
+
+	otherAudience := testClaims(issuer, RoleChannelWrite)
+	otherAudience.Audience = jwt.ClaimStrings{"account"}
+	_, err = validator.Validate(sign(otherAudience))
+	assert.Error(t, err)
+
+	noAudience := testClaims(issuer, RoleChannelWrite)
+	noAudience.Audience = nil
+	_, err = validator.Validate(sign(noAudience))
+	assert.Error(t, err)
+}
@@ -0,0 +1,81 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/services"
+)
+
+// TemplatesHandler manages the registry of message templates, of the account
+// of the account query param or body field, or of the default account.
+type TemplatesHandler struct {
+	TemplateService services.TemplateService
+	WhatsappService services.WhatsappService
+	Accounts        services.WhatsappAccountService
+}
+
+func (h *TemplatesHandler) HandleListTemplates(w http.ResponseWriter, r *http.Request) {
+	templates, err := h.TemplateService.ListTemplates(r.URL.Query().Get("account"))
+	if err != nil {
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, templates)
+}
+
+func (h *TemplatesHandler) HandleSaveTemplate(w http.ResponseWriter, r *http.Request) {
+	template := &models.Template{}
+	if err := json.NewDecoder(r.Body).Decode(template); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	template, err := h.TemplateService.SaveTemplate(template)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTemplate) {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	writeJSON(w, http.StatusOK, template)
+}
+
+func (h *TemplatesHandler) HandleDeleteTemplate(w http.ResponseWriter, r *http.Request) {
+	name, language := chi.URLParam(r, "name"), chi.URLParam(r, "language")
+	if err := h.TemplateService.DeleteTemplate(r.URL.Query().Get("account"), name, language); err != nil {
+		whatsappAccountError(w, fmt.Errorf("template %s %s %w", name, language, err))
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// HandleSyncTemplates saves the templates of the whatsapp api of the account,
+// responding 501 if the api does not manage templates.
+func (h *TemplatesHandler) HandleSyncTemplates(w http.ResponseWriter, r *http.Request) {
+	account := r.URL.Query().Get("account")
+	ws, err := accountWhatsappService(h.WhatsappService, h.Accounts, account)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return
+	}
+	synced, err := h.TemplateService.SyncTemplates(account, ws)
+	if err != nil {
+		if errors.Is(err, services.ErrTemplatesNotSupported) {
+			http.Error(w, err.Error(), http.StatusNotImplemented)
+			return
+		}
+		logger.Error(err.Error())
+		http.Error(w, err.Error(), http.StatusBadGateway)
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]int{"synced": synced})
+}
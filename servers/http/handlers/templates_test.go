@@ -0,0 +1,147 @@
+package handlers
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/go-chi/chi"
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/services"
+)
+
+// fakeTemplates is a template repository keeping the templates in memory.
+type fakeTemplates map[string]*models.Template
+
+func (f fakeTemplates) Upsert(t *models.Template) error {
+	f[t.Account+"/"+t.Name+"/"+t.Language] = t
+	return nil
+}
+
+func (f fakeTemplates) FindOne(account string, name string, language string) (*models.Template, error) {
+	t, ok := f[account+"/"+name+"/"+language]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return t, nil
+}
+
+func (f fakeTemplates) FindAll(account string) ([]*models.Template, error) {
+	templates := []*models.Template{}
+	for _, t := range f {
+		if t.Account == account {
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+func (f fakeTemplates) Delete(account string, name string, language string) error {
+	if _, ok := f[account+"/"+name+"/"+language]; !ok {
+		return repositories.ErrNotFound
+	}
+	delete(f, account+"/"+name+"/"+language)
+	return nil
+}
+
+func (f fakeTemplates) CreateIndexes() error { return nil }
+
+func TestHandleSendTemplate(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	templates := services.NewTemplateService(fakeTemplates{})
+	_, err := templates.SaveTemplate(&models.Template{Name: "welcome", Language: "pt_BR", ParameterCount: 1})
+	assert.NoError(t, err)
+
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().FindContact(&models.Contact{URN: "5582988887777"}).Return(nil, repositories.ErrNotFound)
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockWhatsappService.EXPECT().SendMessage(gomock.Any()).DoAndReturn(func(body []byte) (http.Header, io.ReadCloser, error) {
+		assert.JSONEq(t, `{
+			"to": "5582988887777",
+			"type": "template",
+			"template": {
+				"name": "welcome",
+				"language": {"policy": "deterministic", "code": "pt_BR"},
+				"components": [{"type": "body", "parameters": [{"type": "text", "text": "Maria"}]}]
+			}
+		}`, string(body))
+		return http.Header{}, ioutil.NopCloser(bytes.NewReader([]byte(`{"messages":[]}`))), nil
+	})
+
+	ch := CourierHandler{
+		WhatsappService: mockWhatsappService,
+		ContactService:  mockContactService,
+		TemplateService: templates,
+	}
+	router := chi.NewRouter()
+	router.Post("/v1/messages/template", ch.HandleSendTemplate)
+
+	tcs := []struct {
+		Label   string
+		Payload string
+		Code    int
+	}{
+		{"template", `{"to":"5582988887777","name":"welcome","language":"pt_BR","parameters":["Maria"]}`, http.StatusCreated},
+		{"missing parameter", `{"to":"5582988887777","name":"welcome","language":"pt_BR"}`, http.StatusBadRequest},
+		{"unknown template", `{"to":"5582988887777","name":"welcome","language":"en","parameters":["Maria"]}`, http.StatusNotFound},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Label, func(t *testing.T) {
+			request, _ := http.NewRequest(http.MethodPost, "/v1/messages/template", strings.NewReader(tc.Payload))
+			response := httptest.NewRecorder()
+			router.ServeHTTP(response, request)
+			assert.Equal(t, tc.Code, response.Code)
+		})
+	}
+}
+
+func TestHandleSyncTemplates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockWhatsappService.EXPECT().GetTemplates().Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(strings.NewReader(`{"data":[{"name":"welcome","language":"pt_BR","status":"APPROVED","components":[{"type":"BODY","text":"Olá {{1}}"}]}]}`)),
+	}, nil)
+	mockCloudService := mocks.NewMockWhatsappService(ctrl)
+	mockCloudService.EXPECT().GetTemplates().Return(nil, services.ErrTemplatesNotSupported)
+	accounts := newFakeAccounts()
+	accounts.services["cloud"] = mockCloudService
+
+	th := TemplatesHandler{
+		TemplateService: services.NewTemplateService(fakeTemplates{}),
+		WhatsappService: mockWhatsappService,
+		Accounts:        accounts,
+	}
+	router := chi.NewRouter()
+	router.Post("/integrations/template/sync", th.HandleSyncTemplates)
+	router.Get("/integrations/template", th.HandleListTemplates)
+
+	request, _ := http.NewRequest(http.MethodPost, "/integrations/template/sync", nil)
+	response := httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.JSONEq(t, `{"synced":1}`, response.Body.String())
+
+	request, _ = http.NewRequest(http.MethodGet, "/integrations/template", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusOK, response.Code)
+	assert.Contains(t, response.Body.String(), `"parameter_count":1`)
+
+	request, _ = http.NewRequest(http.MethodPost, "/integrations/template/sync?account=cloud", nil)
+	response = httptest.NewRecorder()
+	router.ServeHTTP(response, request)
+	assert.Equal(t, http.StatusNotImplemented, response.Code)
+}
@@ -8,31 +8,47 @@ import (
 	"io"
 	"io/ioutil"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/go-chi/chi"
 	"github.com/weni/whatsapp-router/config"
 	"github.com/weni/whatsapp-router/logger"
 	"github.com/weni/whatsapp-router/metric"
 	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
 	"github.com/weni/whatsapp-router/services"
 	"github.com/weni/whatsapp-router/utils"
 )
 
 var confirmationMessage = config.GetConfig().Whatsapp.WelcomeMessage
-
-const tokenPrefix = "weni-demo"
+var farewellMessage = config.GetConfig().Whatsapp.FarewellMessage
+var stopKeywords = config.GetConfig().Whatsapp.StopKeywords
+var trustedProxies = config.GetConfig().App.TrustedProxies
 
 type WhatsappHandler struct {
 	ContactService  services.ContactService
+	BindingService  services.ContactBindingService
 	ChannelService  services.ChannelService
 	CourierService  services.CourierService
 	WhatsappService services.WhatsappService
 	ConfigService   services.ConfigService
+	MessageService  services.MessageService
+	Accounts        services.WhatsappAccountService
 	Metrics         *metric.Service
 }
 
+// HandleIncomingRequests handles the webhooks of the default whatsapp account,
+// or of the account in the request path.
 func (h *WhatsappHandler) HandleIncomingRequests(w http.ResponseWriter, r *http.Request) {
+	account := chi.URLParam(r, "account")
+	ws, err := accountWhatsappService(h.WhatsappService, h.Accounts, account)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return
+	}
+
 	incomingWebhookEvent, err := ioutil.ReadAll(io.LimitReader(r.Body, 1000000))
 	r.Body = ioutil.NopCloser(bytes.NewBuffer(incomingWebhookEvent))
 	defer r.Body.Close()
@@ -43,188 +59,494 @@ func (h *WhatsappHandler) HandleIncomingRequests(w http.ResponseWriter, r *http.
 		return
 	}
 
-	payload := &eventPayload{}
-	if err = json.Unmarshal(incomingWebhookEvent, &payload); err != nil {
+	payload, err := parseEventPayload(incomingWebhookEvent)
+	if err != nil {
 		logger.Error(fmt.Sprintf("unable to parse request body: %s", err))
 		w.WriteHeader(http.StatusOK)
 		fmt.Fprint(w, err.Error())
 		return
 	}
 
+	if len(payload.Statuses) > 0 {
+		h.redirectStatuses(payload.Statuses)
+	}
+
+	payload.Messages = h.dropDuplicates(payload.Messages)
 	if len(payload.Messages) <= 0 {
 		w.WriteHeader(http.StatusOK)
 		return
 	}
 
-	cName := ""
-	if len(payload.Contacts) > 0 {
-		cName = payload.Contacts[0].Profile.Name
+	failed := false
+	for _, contactPayload := range payload.splitByContact() {
+		if err := h.handleContactPayload(contactPayload, account, ws); err != nil {
+			logger.Error(fmt.Sprintf("unable to handle messages from %s: %s", contactPayload.Messages[0].From, err))
+			h.unmarkAsProcessed(contactPayload.Messages)
+			failed = true
+		}
 	}
-	incomingContact := &models.Contact{
-		URN:  payload.Messages[0].From,
-		Name: cName,
+	if failed {
+		w.WriteHeader(http.StatusInternalServerError)
+		return
 	}
+	w.WriteHeader(http.StatusOK)
+}
 
-	contact, err := h.ContactService.FindContact(incomingContact)
+// HandleVerifyWebhook answers the webhook subscription handshake of the cloud
+// api, echoing the challenge when the verify token is the one of the account.
+func (h *WhatsappHandler) HandleVerifyWebhook(w http.ResponseWriter, r *http.Request) {
+	ws, err := accountWhatsappService(h.WhatsappService, h.Accounts, chi.URLParam(r, "account"))
 	if err != nil {
-		logger.Debug(err.Error())
+		whatsappAccountError(w, err)
+		return
+	}
+	query := r.URL.Query()
+	verifyToken := ws.Account().VerifyToken
+	if query.Get("hub.mode") != "subscribe" || verifyToken == "" || query.Get("hub.verify_token") != verifyToken {
+		http.Error(w, "invalid verify token", http.StatusForbidden)
+		return
 	}
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	fmt.Fprint(w, query.Get("hub.challenge"))
+}
+
+// signatureHeader is the header with the HMAC-SHA256 signature of webhooks.
+const signatureHeader = "X-Hub-Signature-256"
 
-	textMessage := ""
-	if payload.Messages[0].Type == "text" {
-		textMessage = payload.Messages[0].Text.Body
+// VerifyWebhook rejects the webhooks sent from ips not allowed by the account
+// with 403, reading the ip forwarded by the trusted proxies, and, when the account has a webhook secret, the ones not signed
+// with it with 401.
+func (h *WhatsappHandler) VerifyWebhook(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		account := chi.URLParam(r, "account")
+		ws, err := accountWhatsappService(h.WhatsappService, h.Accounts, account)
+		if err != nil {
+			whatsappAccountError(w, err)
+			return
+		}
+		wa := ws.Account()
+
+		if !utils.AllowedIP(utils.ClientIP(r, trustedProxies), wa.WebhookAllowedIPs) {
+			h.rejectWebhook(w, account, "ip", http.StatusForbidden)
+			return
+		}
+		if wa.WebhookSecret == "" {
+			next(w, r)
+			return
+		}
+		body, err := ioutil.ReadAll(io.LimitReader(r.Body, 1000000))
+		r.Body.Close()
+		if err != nil {
+			logger.Error(fmt.Sprintf("unable to read request body: %s", err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !utils.ValidSignature(wa.WebhookSecret, body, r.Header.Get(signatureHeader)) {
+			h.rejectWebhook(w, account, "signature", http.StatusUnauthorized)
+			return
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		next(w, r)
 	}
+}
 
-	if textMessage != "" && strings.Contains(textMessage, tokenPrefix) {
-		channelFromToken, err := h.ChannelService.FindChannelByToken(textMessage)
+func (h *WhatsappHandler) rejectWebhook(w http.ResponseWriter, account string, reason string, status int) {
+	logger.Debug(fmt.Sprintf("webhook of whatsapp account %q rejected by %s", account, reason))
+	h.Metrics.SaveWebhookRejection(metric.NewWebhookRejection(account, reason))
+	http.Error(w, http.StatusText(status), status)
+}
+
+// dropDuplicates discards the messages already received in a previous
+// delivery of the webhook.
+func (h *WhatsappHandler) dropDuplicates(messages []eventMessage) []eventMessage {
+	var unique []eventMessage
+	for _, msg := range messages {
+		// messages without id can not be told apart from each other
+		if msg.ID == "" {
+			unique = append(unique, msg)
+			continue
+		}
+		isNew, err := h.MessageService.MarkAsProcessed(msg.ID)
 		if err != nil {
-			logger.Debug(err.Error())
+			logger.Error(err.Error())
+			unique = append(unique, msg)
+			continue
 		}
-		if channelFromToken != nil {
-			incomingContact.Channel = channelFromToken.ID
-			if contact != nil {
-				lastContactChannel, err := h.ChannelService.FindChannelById(contact.Channel.Hex())
-				if err != nil {
-					logger.Error(err.Error())
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
-				}
-				contact.Channel = channelFromToken.ID
-				_, err = h.ContactService.UpdateContact(contact)
-				if err != nil {
-					logger.Error(err.Error())
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
-				}
-				_, b, err := h.sendTokenConfirmation(contact)
-				if err != nil {
-					logger.Error(err.Error())
-					w.WriteHeader(http.StatusInternalServerError)
-					return
-				}
+		if !isNew {
+			logger.Debug(fmt.Sprintf("duplicated message %s from %s dropped", msg.ID, msg.From))
+			h.Metrics.SaveDuplicateMessage(metric.NewDuplicateMessage())
+			continue
+		}
+		unique = append(unique, msg)
+	}
+	return unique
+}
 
-				body, _ := ioutil.ReadAll(b)
-				b.Close()
-				logger.Debug(string(body))
-				w.WriteHeader(http.StatusOK)
-
-				contactActivatedMetricDec := metric.NewContactActivated(lastContactChannel.UUID)
-				h.Metrics.DecContactActivated(contactActivatedMetricDec)
-				contactActivatedMetricInc := metric.NewContactActivated(lastContactChannel.UUID)
-				h.Metrics.IncContactActivated(contactActivatedMetricInc)
-				contactActivation := metric.NewContactActivation(channelFromToken.UUID)
-				h.Metrics.SaveContactActivation(contactActivation)
-
-				return
-			} else {
-				_, err := h.ContactService.CreateContact(incomingContact)
-				if err != nil {
-					logger.Error(err.Error())
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
+func (h *WhatsappHandler) unmarkAsProcessed(messages []eventMessage) {
+	for _, msg := range messages {
+		if msg.ID == "" {
+			continue
+		}
+		if err := h.MessageService.UnmarkAsProcessed(msg.ID); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+// handleContactPayload processes, in order, the messages sent by a single
+// contact. Token messages and replies to the channel list bind the contact to
+// a channel, stop keywords unbind it, channel list keywords are answered with
+// the list of its recent channels and every other message is redirected to
+// the courier of the channel the contact is bound to. Only channels of the
+// account are accepted. Contacts whose binding expired are answered with the
+// confirmation message instead. The messages open the customer service window
+// of the contact.
+func (h *WhatsappHandler) handleContactPayload(payload *eventPayload, account string, ws services.WhatsappService) error {
+	incomingContact := &models.Contact{
+		URN:     payload.Messages[0].From,
+		Account: account,
+	}
+	if len(payload.Contacts) > 0 {
+		incomingContact.Name = payload.Contacts[0].Profile.Name
+	}
+	defer h.recordInbound(incomingContact, payload.Messages)
+
+	contact, err := h.ContactService.FindContact(incomingContact)
+	if err != nil {
+		logger.Debug(err.Error())
+	}
+
+	var pending []eventMessage
+	for _, msg := range payload.Messages {
+		if msg.isStop() && contact != nil && !contact.Channel.IsZero() {
+			if err := h.redirectMessages(contact, payload.Contacts, pending); err != nil {
+				return err
+			}
+			pending = nil
+			contact, err = h.deactivateContact(ws, contact, msg.ID)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if msg.isChannelListRequest() {
+			if err := h.redirectMessages(contact, payload.Contacts, pending); err != nil {
+				return err
+			}
+			pending = nil
+			if err := h.sendChannelList(ws, incomingContact); err != nil {
+				return err
+			}
+			continue
+		}
+		if uuid, ok := msg.channelChoice(); ok {
+			if chosen := h.chosenChannel(uuid, account); chosen != nil {
+				if err := h.redirectMessages(contact, payload.Contacts, pending); err != nil {
+					return err
 				}
-				_, b, err := h.sendTokenConfirmation(incomingContact)
+				pending = nil
+				contact, err = h.activateContact(ws, contact, incomingContact, chosen, msg.ID, models.BindingReasonList)
 				if err != nil {
-					logger.Error(err.Error())
-					http.Error(w, err.Error(), http.StatusInternalServerError)
-					return
+					return err
 				}
-				body, _ := ioutil.ReadAll(b)
-				b.Close()
-				logger.Debug(string(body))
-				w.WriteHeader(http.StatusOK)
-
-				contactActivation := metric.NewContactActivation(channelFromToken.UUID)
-				h.Metrics.SaveContactActivation(contactActivation)
-				contactActivated := metric.NewContactActivated(channelFromToken.UUID)
-				h.Metrics.IncContactActivated(contactActivated)
-				return
+				continue
 			}
 		}
-	} else {
-		if contact != nil {
-			channelId := contact.Channel.Hex()
-			channel, err := h.ChannelService.FindChannelById(channelId)
+		if token, ok := msg.token(); ok {
+			channelFromToken, err := h.ChannelService.FindChannelByToken(token)
 			if err != nil {
 				logger.Debug(err.Error())
 			}
-			if channel != nil {
-				channelUUID := channel.UUID
-				status, err := h.CourierService.RedirectMessage(channelUUID, string(incomingWebhookEvent))
-				if err != nil {
-					logger.Debug(err.Error())
-					w.WriteHeader(status)
-					fmt.Fprint(w, err)
-					return
+			if channelFromToken != nil && channelFromToken.Account != account {
+				logger.Debug(fmt.Sprintf("token of channel %s sent to another whatsapp account", channelFromToken.UUID))
+				channelFromToken = nil
+			}
+			if channelFromToken != nil {
+				if err := h.redirectMessages(contact, payload.Contacts, pending); err != nil {
+					return err
 				}
-				if status >= 400 {
-					logger.Debug(fmt.Sprintf("message redirect with status %d for channel %s", status, channelUUID))
-					return
+				pending = nil
+				contact, err = h.activateContact(ws, contact, incomingContact, channelFromToken, msg.ID, models.BindingReasonToken)
+				if err != nil {
+					return err
 				}
-				cmm := metric.NewContactMessage(channelUUID)
-				h.Metrics.SaveContactMessage(cmm)
-				w.WriteHeader(http.StatusOK)
-				return
+				continue
 			}
-			logger.Debug("channel not found")
-			w.WriteHeader(http.StatusOK)
-			return
 		}
+		if contact != nil && contact.Channel.IsZero() && contact.BindingExpired {
+			if err := h.remindExpiredBinding(ws, contact); err != nil {
+				return err
+			}
+			continue
+		}
+		pending = append(pending, msg)
 	}
+	return h.redirectMessages(contact, payload.Contacts, pending)
+}
 
-	//returning status ok to avoid retry send mechanisms if contact not exists or token is not valid
-	logger.Debug("contact not found and token not valid")
-	w.WriteHeader(http.StatusOK)
-	fmt.Fprint(w, errors.New("contact not found and token not valid"))
+// activateContact binds the contact to the channel of the token or list reply
+// of the message with messageID, creating the contact when it does not exist
+// yet, and sends the token confirmation message.
+func (h *WhatsappHandler) activateContact(ws services.WhatsappService, contact *models.Contact, incomingContact *models.Contact, channel *models.Channel, messageID string, reason string) (*models.Contact, error) {
+	binding := &models.ContactBinding{
+		URN:       incomingContact.URN,
+		Account:   incomingContact.Account,
+		Event:     models.BindingBind,
+		Channel:   channel.ID,
+		MessageID: messageID,
+		Reason:    reason,
+	}
+	if contact != nil {
+		binding.PreviousChannel = contact.Channel
+		var lastContactChannel *models.Channel
+		if !contact.Channel.IsZero() {
+			var err error
+			lastContactChannel, err = h.ChannelService.FindChannelById(contact.Channel.Hex())
+			if err != nil {
+				return nil, err
+			}
+		}
+		contact.Channel = channel.ID
+		if _, err := h.ContactService.UpdateContact(contact); err != nil {
+			return nil, err
+		}
+		recordBinding(h.BindingService, binding)
+		if err := h.confirmToken(ws, contact, channel); err != nil {
+			return nil, err
+		}
+
+		if lastContactChannel != nil {
+			contactActivatedMetricDec := metric.NewContactActivated(lastContactChannel.UUID)
+			h.Metrics.DecContactActivated(contactActivatedMetricDec)
+		}
+		contactActivatedMetricInc := metric.NewContactActivated(channel.UUID)
+		h.Metrics.IncContactActivated(contactActivatedMetricInc)
+		contactActivation := metric.NewContactActivation(channel.UUID)
+		h.Metrics.SaveContactActivation(contactActivation)
+		return contact, nil
+	}
+
+	incomingContact.Channel = channel.ID
+	if _, err := h.ContactService.CreateContact(incomingContact); err != nil {
+		return nil, err
+	}
+	recordBinding(h.BindingService, binding)
+	if err := h.confirmToken(ws, incomingContact, channel); err != nil {
+		return nil, err
+	}
+
+	contactActivation := metric.NewContactActivation(channel.UUID)
+	h.Metrics.SaveContactActivation(contactActivation)
+	contactActivated := metric.NewContactActivated(channel.UUID)
+	h.Metrics.IncContactActivated(contactActivated)
+	return incomingContact, nil
 }
 
-func (h *WhatsappHandler) RefreshToken(w http.ResponseWriter, r *http.Request) {
-	res, err := h.WhatsappService.Login()
+// deactivateContact unbinds the contact from its channel, on the stop keyword
+// of the message with messageID, and sends the farewell message.
+func (h *WhatsappHandler) deactivateContact(ws services.WhatsappService, contact *models.Contact, messageID string) (*models.Contact, error) {
+	lastContactChannel, err := h.ChannelService.FindChannelById(contact.Channel.Hex())
 	if err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprint(w, err.Error())
+		logger.Debug(err.Error())
+	}
+	unbound, err := h.ContactService.UnbindContact(contact)
+	if err != nil {
+		return nil, err
+	}
+	recordBinding(h.BindingService, &models.ContactBinding{
+		URN:             contact.URN,
+		Account:         contact.Account,
+		Event:           models.BindingUnbind,
+		PreviousChannel: contact.Channel,
+		MessageID:       messageID,
+		Reason:          models.BindingReasonStop,
+	})
+	if err := sendText(ws, unbound.URN, farewellMessage); err != nil {
+		return nil, err
+	}
+	if lastContactChannel != nil {
+		contactActivated := metric.NewContactActivated(lastContactChannel.UUID)
+		h.Metrics.DecContactActivated(contactActivated)
+	}
+	return unbound, nil
+}
+
+// remindExpiredBinding answers the first message of a contact whose binding
+// expired with the confirmation message, which tells how to start a flow.
+func (h *WhatsappHandler) remindExpiredBinding(ws services.WhatsappService, contact *models.Contact) error {
+	if err := sendText(ws, contact.URN, confirmationMessage); err != nil {
+		return err
+	}
+	if err := h.ContactService.ClearBindingExpired(contact); err != nil {
 		logger.Error(err.Error())
-		return
 	}
+	contact.BindingExpired = false
+	return nil
+}
+
+// confirmToken sends the welcome message of the channel to the contact, in
+// the language of its phone number when the channel has one.
+func (h *WhatsappHandler) confirmToken(ws services.WhatsappService, contact *models.Contact, channel *models.Channel) error {
+	return sendText(ws, contact.URN, welcomeMessage(channel, contact.URN))
+}
 
-	var login services.LoginWhatsapp
+// welcomeMessage returns the welcome message of the channel in the language
+// of the urn, falling back to the default message of the channel and then to
+// the configured confirmation message.
+func welcomeMessage(channel *models.Channel, urn string) string {
+	if text, ok := channel.WelcomeMessages[utils.LanguageFromURN(urn)]; ok && text != "" {
+		return text
+	}
+	if channel.WelcomeMessage != "" {
+		return channel.WelcomeMessage
+	}
+	return confirmationMessage
+}
 
-	bdBytes, err := io.ReadAll(res.Body)
-	defer res.Body.Close()
+// recordInbound records the time of the last of the messages received from
+// the contact, if the contact is bound or was bound to a channel.
+func (h *WhatsappHandler) recordInbound(contact *models.Contact, messages []eventMessage) {
+	var last time.Time
+	for _, msg := range messages {
+		if sent := msg.sentAt(); sent.After(last) {
+			last = sent
+		}
+	}
+	err := h.ContactService.RecordInbound(contact, last)
+	if errors.Is(err, repositories.ErrNotFound) {
+		return
+	}
 	if err != nil {
 		logger.Error(err.Error())
-		return
 	}
+}
 
-	bdString := string(bdBytes)
+// redirectMessages sends the messages to the courier of the channel the
+// contact is bound to, logging the outcome of each message.
+func (h *WhatsappHandler) redirectMessages(contact *models.Contact, contacts []eventContact, messages []eventMessage) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	if contact == nil {
+		//messages are dropped without error to avoid retry send mechanisms if contact not exists or token is not valid
+		logger.Debug("contact not found and token not valid")
+		return nil
+	}
+	if contact.Channel.IsZero() {
+		logger.Debug("contact not bound to a channel and token not valid")
+		return nil
+	}
 
-	if res.StatusCode != 200 {
-		w.WriteHeader(res.StatusCode)
-		w.Write(bdBytes)
-		logger.Error(fmt.Sprintf("Couldn't update token: %s", bdString))
-		return
+	channel, err := h.ChannelService.FindChannelById(contact.Channel.Hex())
+	if err != nil {
+		logger.Debug(err.Error())
+	}
+	if channel == nil {
+		logger.Debug("channel not found")
+		return nil
 	}
 
-	if err := json.Unmarshal(bdBytes, &login); err != nil {
-		w.WriteHeader(http.StatusInternalServerError)
-		fmt.Fprint(w, err.Error())
-		logger.Error(err.Error())
+	msgPayload, err := json.Marshal(&eventPayload{Contacts: contacts, Messages: messages})
+	if err != nil {
+		return err
+	}
+	status, err := h.CourierService.RedirectMessage(channel.UUID, string(msgPayload))
+	if err != nil {
+		return err
+	}
+	for _, msg := range messages {
+		logger.Debug(fmt.Sprintf("message %s from %s redirected with status %d for channel %s", msg.ID, msg.From, status, channel.UUID))
+	}
+	if status >= 400 {
+		return nil
+	}
+	for range messages {
+		cmm := metric.NewContactMessage(channel.UUID)
+		h.Metrics.SaveContactMessage(cmm)
+	}
+	return nil
+}
+
+// redirectStatuses forwards each status event to the courier of the channel
+// that sent the message it refers to.
+func (h *WhatsappHandler) redirectStatuses(statuses []json.RawMessage) {
+	for _, rawStatus := range statuses {
+		var st eventStatus
+		if err := json.Unmarshal(rawStatus, &st); err != nil {
+			logger.Error(fmt.Sprintf("unable to parse status: %s", err))
+			continue
+		}
+		msg, err := h.MessageService.FindMessageByMessageID(st.ID)
+		if err != nil {
+			logger.Debug(err.Error())
+			continue
+		}
+		channel, err := h.ChannelService.FindChannelById(msg.Channel.Hex())
+		if err != nil {
+			logger.Debug(err.Error())
+			continue
+		}
+		statusPayload, err := json.Marshal(struct {
+			Statuses []json.RawMessage `json:"statuses"`
+		}{[]json.RawMessage{rawStatus}})
+		if err != nil {
+			logger.Error(err.Error())
+			continue
+		}
+		status, err := h.CourierService.RedirectMessage(channel.UUID, string(statusPayload))
+		if err != nil {
+			logger.Error(err.Error())
+			continue
+		}
+		if status >= 400 {
+			logger.Debug(fmt.Sprintf("status %s redirect with status %d for channel %s", st.ID, status, channel.UUID))
+		}
+	}
+}
+
+// HandleLogin answers the token refresh of courier with the credential of
+// the authenticated channel, in the format of the on-premises users/login
+// endpoint. The login of the whatsapp account is never proxied, as its token
+// grants admin access to the account.
+func (h *WhatsappHandler) HandleLogin(w http.ResponseWriter, r *http.Request) {
+	channel := authenticatedChannel(r)
+	if channel == nil {
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "invalid channel credential", http.StatusUnauthorized)
 		return
 	}
+	writeJSON(w, http.StatusOK, channelLogin(channel, time.Now()))
+}
 
-	newToken := login.Users[0].Token
+// loginTTL is the expiration announced for the channel credential, which lasts
+// until it is rotated, so that courier keeps refreshing it as it does with the
+// on-premises tokens.
+const loginTTL = 7 * 24 * time.Hour
 
-	config.UpdateAuthToken(newToken)
+type loginUser struct {
+	Token        string `json:"token"`
+	ExpiresAfter string `json:"expires_after"`
+}
 
-	h.ConfigService.CreateOrUpdate(&models.Config{Token: newToken})
+type loginResponse struct {
+	Users []loginUser `json:"users"`
+}
 
-	utils.CopyHeader(w.Header(), res.Header)
-	w.WriteHeader(res.StatusCode)
-	w.Write(bdBytes)
+func channelLogin(channel *models.Channel, now time.Time) loginResponse {
+	return loginResponse{Users: []loginUser{{
+		Token:        channel.Credential,
+		ExpiresAfter: now.Add(loginTTL).UTC().Format("2006-01-02 15:04:05-07:00"),
+	}}}
 }
 
 func (h *WhatsappHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
-	res, err := h.WhatsappService.Health()
+	ws, err := h.channelWhatsappService(r)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return
+	}
+	res, err := ws.Health()
 	if err != nil {
 		logger.Error(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -236,8 +558,13 @@ func (h *WhatsappHandler) HandleHealth(w http.ResponseWriter, r *http.Request) {
 }
 
 func (h *WhatsappHandler) HandleGetMedia(w http.ResponseWriter, r *http.Request) {
+	ws, err := h.channelWhatsappService(r)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return
+	}
 	mediaID := chi.URLParam(r, "mediaID")
-	res, err := h.WhatsappService.GetMedia(r.Header, mediaID)
+	res, err := ws.GetMedia(r.Header, mediaID)
 	if err != nil {
 		logger.Error(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -250,7 +577,12 @@ func (h *WhatsappHandler) HandleGetMedia(w http.ResponseWriter, r *http.Request)
 }
 
 func (h *WhatsappHandler) HandlePostMedia(w http.ResponseWriter, r *http.Request) {
-	res, err := h.WhatsappService.PostMedia(r.Header, r.Body)
+	ws, err := h.channelWhatsappService(r)
+	if err != nil {
+		whatsappAccountError(w, err)
+		return
+	}
+	res, err := ws.PostMedia(r.Header, r.Body)
 	if err != nil {
 		logger.Error(err.Error())
 		http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -262,32 +594,288 @@ func (h *WhatsappHandler) HandlePostMedia(w http.ResponseWriter, r *http.Request
 	res.Body.Close()
 }
 
-func (h *WhatsappHandler) sendTokenConfirmation(contact *models.Contact) (http.Header, io.ReadCloser, error) {
-	urn := contact.URN
-	payload := fmt.Sprintf(
-		`{"to":"%s","type":"text","text":{"body":"%s"}}`,
-		urn,
-		confirmationMessage,
-	)
-	payloadBytes := []byte(payload)
+// channelWhatsappService returns the whatsapp service of the account of the
+// channel in the request path, or the default one if there is no channel.
+func (h *WhatsappHandler) channelWhatsappService(r *http.Request) (services.WhatsappService, error) {
+	account, err := channelAccount(r, h.ChannelService)
+	if err != nil {
+		return nil, err
+	}
+	return accountWhatsappService(h.WhatsappService, h.Accounts, account)
+}
+
+// sendText sends a text message to the contact.
+func sendText(ws services.WhatsappService, urn string, text string) error {
+	payload, err := json.Marshal(textMessage{
+		To:   urn,
+		Type: "text",
+		Text: textBody{Body: text},
+	})
+	if err != nil {
+		return err
+	}
+	return sendPayload(ws, payload)
+}
 
-	return h.WhatsappService.SendMessage(payloadBytes)
+// sendPayload sends the message payload, logging the response of the whatsapp
+// api.
+func sendPayload(ws services.WhatsappService, payload []byte) error {
+	_, b, err := ws.SendMessage(payload)
+	if err != nil {
+		return err
+	}
+	body, _ := ioutil.ReadAll(b)
+	b.Close()
+	logger.Debug(string(body))
+	return nil
+}
+
+type textMessage struct {
+	To   string   `json:"to"`
+	Type string   `json:"type"`
+	Text textBody `json:"text"`
+}
+
+type textBody struct {
+	Body string `json:"body"`
 }
 
 type eventPayload struct {
-	Contacts []struct {
-		Profile struct {
-			Name string `json:"name"`
-		} `json:"profile"`
-		WaID string `json:"wa_id"`
-	} `json:"contacts"`
-	Messages []struct {
-		From      string `json:"from"      validate:"required"`
-		ID        string `json:"id"        validate:"required"`
-		Timestamp string `json:"timestamp" validate:"required"`
-		Type      string `json:"type"      validate:"required"`
-		Text      struct {
-			Body string `json:"body"`
-		} `json:"text"`
+	Contacts []eventContact    `json:"contacts,omitempty"`
+	Messages []eventMessage    `json:"messages,omitempty"`
+	Statuses []json.RawMessage `json:"statuses,omitempty"`
+}
+
+// cloudPayload is the webhook envelope of the cloud api, whose changes carry
+// the contacts, messages and statuses in the on-premises api format.
+type cloudPayload struct {
+	Object string `json:"object"`
+	Entry  []struct {
+		Changes []struct {
+			Field string       `json:"field"`
+			Value eventPayload `json:"value"`
+		} `json:"changes"`
+	} `json:"entry"`
+}
+
+// parseEventPayload parses webhooks of both the on-premises and the cloud api,
+// merging the changes of cloud api webhooks into a single payload so they are
+// routed, and redirected to courier, as on-premises ones.
+func parseEventPayload(data []byte) (*eventPayload, error) {
+	var cloud cloudPayload
+	if err := json.Unmarshal(data, &cloud); err != nil {
+		return nil, err
+	}
+	if cloud.Object == "" && len(cloud.Entry) == 0 {
+		payload := &eventPayload{}
+		if err := json.Unmarshal(data, payload); err != nil {
+			return nil, err
+		}
+		return payload, nil
+	}
+
+	payload := &eventPayload{}
+	for _, entry := range cloud.Entry {
+		for _, change := range entry.Changes {
+			if change.Field != "messages" {
+				continue
+			}
+			payload.Contacts = append(payload.Contacts, change.Value.Contacts...)
+			payload.Messages = append(payload.Messages, change.Value.Messages...)
+			payload.Statuses = append(payload.Statuses, change.Value.Statuses...)
+		}
+	}
+	return payload, nil
+}
+
+// splitByContact groups the messages of the payload by sender, keeping the
+// order in which senders first appear, so each contact can be routed to its
+// own channel.
+func (p *eventPayload) splitByContact() []*eventPayload {
+	var payloads []*eventPayload
+	bySender := map[string]*eventPayload{}
+	for _, msg := range p.Messages {
+		contactPayload, ok := bySender[msg.From]
+		if !ok {
+			contactPayload = &eventPayload{}
+			bySender[msg.From] = contactPayload
+			payloads = append(payloads, contactPayload)
+		}
+		contactPayload.Messages = append(contactPayload.Messages, msg)
+	}
+	for sender, contactPayload := range bySender {
+		if c, ok := p.senderContact(sender, bySender); ok {
+			contactPayload.Contacts = []eventContact{c}
+		}
+	}
+	return payloads
+}
+
+// senderContact returns the contact of the sender. WhatsApp may normalize the
+// wa_id of the contact differently from the sender, as with the ninth digit of
+// brazilian numbers, so the contact of payloads with a single sender, or with
+// a single contact not of another sender, is the sender's even if their
+// numbers do not match.
+func (p *eventPayload) senderContact(sender string, senders map[string]*eventPayload) (eventContact, bool) {
+	for _, c := range p.Contacts {
+		if c.WaID == sender {
+			return c, true
+		}
+	}
+	if len(p.Contacts) == 0 {
+		return eventContact{}, false
+	}
+	if len(senders) == 1 {
+		return p.Contacts[0], true
+	}
+	if _, ok := senders[p.Contacts[0].WaID]; len(p.Contacts) == 1 && !ok {
+		return p.Contacts[0], true
+	}
+	return eventContact{}, false
+}
+
+type eventContact struct {
+	Profile struct {
+		Name string `json:"name"`
+	} `json:"profile"`
+	WaID string `json:"wa_id"`
+
+	raw json.RawMessage
+}
+
+// UnmarshalJSON keeps the raw contact, so all of its fields are preserved
+// when it is redirected to courier.
+func (c *eventContact) UnmarshalJSON(data []byte) error {
+	type contact eventContact
+	var cont contact
+	if err := json.Unmarshal(data, &cont); err != nil {
+		return err
+	}
+	*c = eventContact(cont)
+	c.raw = append(json.RawMessage{}, data...)
+	return nil
+}
+
+func (c eventContact) MarshalJSON() ([]byte, error) {
+	if c.raw != nil {
+		return c.raw, nil
 	}
+	type contact eventContact
+	return json.Marshal(contact(c))
+}
+
+type eventMessage struct {
+	From      string `json:"from"      validate:"required"`
+	ID        string `json:"id"        validate:"required"`
+	Timestamp string `json:"timestamp" validate:"required"`
+	Type      string `json:"type"      validate:"required"`
+	Text      struct {
+		Body string `json:"body"`
+	} `json:"text"`
+	Button struct {
+		Payload string `json:"payload"`
+		Text    string `json:"text"`
+	} `json:"button"`
+	Interactive struct {
+		Type        string                `json:"type"`
+		ButtonReply eventInteractiveReply `json:"button_reply"`
+		ListReply   eventInteractiveReply `json:"list_reply"`
+	} `json:"interactive"`
+
+	raw json.RawMessage
+}
+
+type eventInteractiveReply struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// sentAt returns the time the message was sent, or the current time if the
+// message has no valid timestamp.
+func (m eventMessage) sentAt() time.Time {
+	seconds, err := strconv.ParseInt(m.Timestamp, 10, 64)
+	if err != nil || seconds <= 0 {
+		return time.Now()
+	}
+	return time.Unix(seconds, 0)
+}
+
+// isStop reports whether the message is one of the stop keywords, sent by
+// contacts to unbind from their channel.
+func (m eventMessage) isStop() bool {
+	return m.isKeyword(stopKeywords)
+}
+
+// isChannelListRequest reports whether the message is one of the keywords
+// sent by contacts to choose among their recent channels.
+func (m eventMessage) isChannelListRequest() bool {
+	return m.isKeyword(channelListConfig.Keywords)
+}
+
+// isKeyword reports whether the message is a text with one of the keywords,
+// ignoring case and surrounding spaces.
+func (m eventMessage) isKeyword(keywords []string) bool {
+	if m.Type != "text" {
+		return false
+	}
+	text := strings.TrimSpace(m.Text.Body)
+	for _, keyword := range keywords {
+		if strings.EqualFold(text, strings.TrimSpace(keyword)) {
+			return true
+		}
+	}
+	return false
+}
+
+// token returns the channel token sent in the message, either typed in a text
+// message or chosen in a button or list reply.
+func (m eventMessage) token() (string, bool) {
+	var candidates []string
+	switch m.Type {
+	case "text":
+		candidates = []string{m.Text.Body}
+	case "button":
+		candidates = []string{m.Button.Payload, m.Button.Text}
+	case "interactive":
+		switch m.Interactive.Type {
+		case "button_reply":
+			candidates = []string{m.Interactive.ButtonReply.ID, m.Interactive.ButtonReply.Title}
+		case "list_reply":
+			candidates = []string{m.Interactive.ListReply.ID, m.Interactive.ListReply.Title}
+		}
+	}
+	for _, c := range candidates {
+		if token, ok := utils.ExtractToken(c); ok {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+// UnmarshalJSON keeps the raw message, so all of its fields are preserved
+// when it is redirected to courier.
+func (m *eventMessage) UnmarshalJSON(data []byte) error {
+	type message eventMessage
+	var msg message
+	if err := json.Unmarshal(data, &msg); err != nil {
+		return err
+	}
+	*m = eventMessage(msg)
+	m.raw = append(json.RawMessage{}, data...)
+	return nil
+}
+
+func (m eventMessage) MarshalJSON() ([]byte, error) {
+	if m.raw != nil {
+		return m.raw, nil
+	}
+	type message eventMessage
+	return json.Marshal(message(m))
+}
+
+type eventStatus struct {
+	ID          string `json:"id"`
+	RecipientID string `json:"recipient_id"`
+	Status      string `json:"status"`
+	Timestamp   string `json:"timestamp"`
 }
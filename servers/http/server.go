@@ -22,14 +22,20 @@ type Server struct {
 	db         *mongo.Database
 	httpServer *http.Server
 	metrics    *metric.Service
+	tokenStore services.TokenStore
+	tokens     services.TokenManager
+	accounts   services.WhatsappAccountService
 }
 
-func NewServer(db *mongo.Database, metrics *metric.Service) *Server {
+func NewServer(db *mongo.Database, metrics *metric.Service, tokenStore services.TokenStore, tokens services.TokenManager, accounts services.WhatsappAccountService) *Server {
 	conf := config.GetConfig()
 	return &Server{
-		db:      db,
-		config:  *conf,
-		metrics: metrics,
+		db:         db,
+		config:     *conf,
+		metrics:    metrics,
+		tokenStore: tokenStore,
+		tokens:     tokens,
+		accounts:   accounts,
 	}
 }
 
@@ -60,19 +66,52 @@ func NewRouter(s *Server) *chi.Mux {
 	contactRepoDb := repositories.NewContactRepositoryDb(s.db)
 	channelRepoDb := repositories.NewChannelRepositoryDb(s.db)
 	configRepoDb := repositories.NewConfigRepository(s.db)
+	messageRepoDb := repositories.NewMessageRepositoryDb(s.db)
+	forwardRepoDb := repositories.NewForwardRepositoryDb(s.db)
+	processedMessageRepoDb := repositories.NewProcessedMessageRepositoryDb(s.db)
+	accountRepoDb := repositories.NewWhatsappAccountRepositoryDb(s.db)
+	templateRepoDb := repositories.NewTemplateRepositoryDb(s.db)
+	bindingRepoDb := repositories.NewContactBindingRepositoryDb(s.db)
+	whatsappService := services.NewAccountWhatsappService(services.DefaultWhatsappAccount(), s.tokenStore, s.tokens)
 	whatsappHandler := handlers.WhatsappHandler{
 		ContactService:  services.NewContactService(contactRepoDb),
-		ChannelService:  services.NewChannelService(channelRepoDb, s.metrics),
-		CourierService:  services.NewCourierService(),
-		WhatsappService: services.NewWhatsappService(),
+		BindingService:  services.NewContactBindingService(bindingRepoDb),
+		ChannelService:  services.NewChannelService(channelRepoDb, contactRepoDb, accountRepoDb, bindingRepoDb, s.metrics),
+		CourierService:  services.NewCourierService(forwardRepoDb),
+		WhatsappService: whatsappService,
 		ConfigService:   services.NewConfigService(configRepoDb),
+		MessageService:  services.NewMessageService(messageRepoDb, processedMessageRepoDb),
+		Accounts:        s.accounts,
 		Metrics:         s.metrics,
 	}
 	courierHandler := handlers.CourierHandler{
-		WhatsappService: services.NewWhatsappService(),
+		WhatsappService: whatsappService,
+		ContactService:  services.NewContactService(contactRepoDb),
+		MessageService:  services.NewMessageService(messageRepoDb, processedMessageRepoDb),
+		ChannelService:  services.NewChannelService(channelRepoDb, contactRepoDb, accountRepoDb, bindingRepoDb, s.metrics),
+		Accounts:        s.accounts,
+		TemplateService: services.NewTemplateService(templateRepoDb),
+		Metrics:         s.metrics,
 	}
 	integrationsHandler := handlers.IntegrationsHandler{
-		ChannelService: services.NewChannelService(channelRepoDb, s.metrics),
+		ChannelService: services.NewChannelService(channelRepoDb, contactRepoDb, accountRepoDb, bindingRepoDb, s.metrics),
+	}
+	accountsHandler := handlers.AccountsHandler{
+		Accounts: s.accounts,
+	}
+	templatesHandler := handlers.TemplatesHandler{
+		TemplateService: services.NewTemplateService(templateRepoDb),
+		WhatsappService: whatsappService,
+		Accounts:        s.accounts,
+	}
+	contactsHandler := handlers.ContactsHandler{
+		ContactService: services.NewContactService(contactRepoDb),
+		BindingService: services.NewContactBindingService(bindingRepoDb),
+		ChannelService: services.NewChannelService(channelRepoDb, contactRepoDb, accountRepoDb, bindingRepoDb, s.metrics),
+		Metrics:        s.metrics,
+	}
+	deadLetterHandler := handlers.DeadLetterHandler{
+		CourierService: services.NewCourierService(forwardRepoDb),
 	}
 
 	router.Use(logger.MiddlewareLogger)
@@ -80,22 +119,64 @@ func NewRouter(s *Server) *chi.Mux {
 	router.Route("/wr/", func(r chi.Router) {
 		r.Use(ContentTypeJson)
 		r.Route("/receive", func(r chi.Router) {
-			r.Post("/", whatsappHandler.HandleIncomingRequests)
+			r.Get("/", whatsappHandler.HandleVerifyWebhook)
+			r.Post("/", whatsappHandler.VerifyWebhook(whatsappHandler.HandleIncomingRequests))
+			r.Get("/{account}", whatsappHandler.HandleVerifyWebhook)
+			r.Post("/{account}", whatsappHandler.VerifyWebhook(whatsappHandler.HandleIncomingRequests))
 		})
 	})
 
-	router.Route("/v1", func(r chi.Router) {
+	// whatsapp api routes used by courier, authenticated with the credential
+	// of the channel and sent to the whatsapp account of the channel
+	channelAuth := handlers.ChannelAuth(courierHandler.ChannelService)
+	whatsappRoutes := func(r chi.Router) {
+		r.Use(channelAuth)
 		r.Post("/messages", courierHandler.HandleSendMessage)
-		r.Post("/users/login", whatsappHandler.RefreshToken)
+		r.Post("/messages/template", courierHandler.HandleSendTemplate)
 		r.Get("/health", whatsappHandler.HandleHealth)
 		r.Get("/media/{mediaID}", whatsappHandler.HandleGetMedia)
 		r.Post("/media", whatsappHandler.HandlePostMedia)
+		r.Post("/users/login", whatsappHandler.HandleLogin)
 		r.Patch("/settings/application", func(w http.ResponseWriter, r *http.Request) {
 			w.WriteHeader(http.StatusOK)
 		})
+	}
+	router.Route("/v1", whatsappRoutes)
+	router.Route("/channel/{uuid}/v1", whatsappRoutes)
+
+	router.Route("/integrations/channel", func(r chi.Router) {
+		r.Post("/", handlers.KeycloackAuth(integrationsHandler.HandleCreateChannel, handlers.RoleChannelWrite))
+		r.Get("/", handlers.KeycloackAuth(integrationsHandler.HandleListChannels, handlers.RoleChannelRead))
+		r.Get("/{uuid}", handlers.KeycloackAuth(integrationsHandler.HandleGetChannel, handlers.RoleChannelRead))
+		r.Patch("/{uuid}", handlers.KeycloackAuth(integrationsHandler.HandleUpdateChannel, handlers.RoleChannelWrite))
+		r.Delete("/{uuid}", handlers.KeycloackAuth(integrationsHandler.HandleDeleteChannel, handlers.RoleChannelWrite))
+		r.Post("/{uuid}/rotate-token", handlers.KeycloackAuth(integrationsHandler.HandleRotateChannelToken, handlers.RoleChannelWrite))
+		r.Post("/{uuid}/rotate-credential", handlers.KeycloackAuth(integrationsHandler.HandleRotateChannelCredential, handlers.RoleChannelWrite))
+	})
+
+	router.Route("/integrations/account", func(r chi.Router) {
+		r.Post("/", handlers.KeycloackAuth(accountsHandler.HandleCreateAccount, handlers.RoleAccountWrite))
+		r.Get("/", handlers.KeycloackAuth(accountsHandler.HandleListAccounts, handlers.RoleAccountRead))
+		r.Delete("/{name}", handlers.KeycloackAuth(accountsHandler.HandleDeleteAccount, handlers.RoleAccountWrite))
+	})
+
+	router.Route("/integrations/template", func(r chi.Router) {
+		r.Post("/", handlers.KeycloackAuth(templatesHandler.HandleSaveTemplate, handlers.RoleTemplateWrite))
+		r.Get("/", handlers.KeycloackAuth(templatesHandler.HandleListTemplates, handlers.RoleTemplateRead))
+		r.Post("/sync", handlers.KeycloackAuth(templatesHandler.HandleSyncTemplates, handlers.RoleTemplateWrite))
+		r.Delete("/{name}/{language}", handlers.KeycloackAuth(templatesHandler.HandleDeleteTemplate, handlers.RoleTemplateWrite))
+	})
+
+	router.Route("/integrations/contact", func(r chi.Router) {
+		r.Get("/", handlers.KeycloackAuth(contactsHandler.HandleListContacts, handlers.RoleContactRead))
+		r.Get("/{urn}", handlers.KeycloackAuth(contactsHandler.HandleGetContact, handlers.RoleContactRead))
+		r.Get("/{urn}/bindings", handlers.KeycloackAuth(contactsHandler.HandleListBindings, handlers.RoleContactRead))
+		r.Put("/{urn}/channel", handlers.KeycloackAuth(contactsHandler.HandleReassignContact, handlers.RoleContactWrite))
+		r.Delete("/{urn}/channel", handlers.KeycloackAuth(contactsHandler.HandleUnbindContact, handlers.RoleContactWrite))
 	})
 
-	router.Post("/integrations/channel", handlers.KeycloackAuth(integrationsHandler.HandleCreateChannel))
+	router.Get("/admin/dead-letters", handlers.KeycloackAuth(deadLetterHandler.HandleListDeadLetters, handlers.RoleDeadLetterRead))
+	router.Post("/admin/dead-letters/{id}/replay", handlers.KeycloackAuth(deadLetterHandler.HandleReplayDeadLetter, handlers.RoleDeadLetterWrite))
 
 	router.Get("/", func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusOK)
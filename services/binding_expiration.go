@@ -0,0 +1,72 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/metric"
+	"github.com/weni/whatsapp-router/models"
+)
+
+// expirationPageSize is how many channels are read at a time when expiring
+// bindings.
+const expirationPageSize = 100
+
+// BindingExpiration expires the binding of contacts to the channels with a
+// binding TTL or inactivity timeout.
+type BindingExpiration struct {
+	channels ChannelService
+	contacts ContactService
+	bindings ContactBindingService
+	metrics  *metric.Service
+}
+
+// ExpireBindings unbinds the contacts whose binding expired, recording it in
+// their binding history and decrementing the contacts activated in each
+// channel.
+func (e BindingExpiration) ExpireBindings() {
+	now := time.Now().UTC()
+	for page := int64(1); ; page++ {
+		channels, err := e.channels.ListChannelsDefault(page, expirationPageSize)
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		for _, ch := range channels {
+			if ch.ExpiresBindings() {
+				e.expireChannelBindings(ch, now)
+			}
+		}
+		if len(channels) < expirationPageSize {
+			return
+		}
+	}
+}
+
+func (e BindingExpiration) expireChannelBindings(ch *models.Channel, now time.Time) {
+	expired, err := e.contacts.ExpireBindings(ch, now)
+	if err != nil {
+		logger.Error(fmt.Sprintf("unable to expire bindings of channel %s: %s", ch.UUID, err))
+	}
+	for _, contact := range expired {
+		err := e.bindings.RecordBinding(&models.ContactBinding{
+			URN:             contact.URN,
+			Account:         contact.Account,
+			Event:           models.BindingUnbind,
+			PreviousChannel: ch.ID,
+			Reason:          models.BindingReasonExpired,
+		})
+		if err != nil {
+			logger.Error(fmt.Sprintf("unable to record unbind of contact %s: %s", contact.URN, err))
+		}
+		e.metrics.DecContactActivated(metric.NewContactActivated(ch.UUID))
+	}
+	if len(expired) > 0 {
+		logger.Debug(fmt.Sprintf("%d bindings of channel %s expired", len(expired), ch.UUID))
+	}
+}
+
+func NewBindingExpiration(channels ChannelService, contacts ContactService, bindings ContactBindingService, metricService *metric.Service) BindingExpiration {
+	return BindingExpiration{channels, contacts, bindings, metricService}
+}
@@ -0,0 +1,45 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/metric"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func TestExpireBindings(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+
+	expiring := &models.Channel{ID: primitive.NewObjectID(), UUID: "6d0bd2fe-0e36-4c0d-a53a-4b9ea8d32b50", InactivityTimeout: 3600}
+	failing := &models.Channel{ID: primitive.NewObjectID(), UUID: "0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1", BindingTTL: 86400}
+	permanent := &models.Channel{ID: primitive.NewObjectID(), UUID: "9e3f8a0b-6a51-4b2c-8f7d-1c2d3e4f5a6b"}
+
+	mockChannelService := mocks.NewMockChannelService(ctrl)
+	mockChannelService.EXPECT().ListChannelsDefault(int64(1), int64(expirationPageSize)).Return(
+		[]*models.Channel{expiring, failing, permanent}, nil,
+	)
+	mockContactService := mocks.NewMockContactService(ctrl)
+	mockContactService.EXPECT().ExpireBindings(expiring, gomock.Any()).Return(
+		[]*models.Contact{{URN: "5582988887777", Account: "acme", BindingExpired: true}}, nil,
+	)
+	mockContactService.EXPECT().ExpireBindings(failing, gomock.Any()).Return(nil, errors.New("unexpected database error"))
+	mockBindingService := mocks.NewMockContactBindingService(ctrl)
+	mockBindingService.EXPECT().RecordBinding(&models.ContactBinding{
+		URN:             "5582988887777",
+		Account:         "acme",
+		Event:           models.BindingUnbind,
+		PreviousChannel: expiring.ID,
+		Reason:          models.BindingReasonExpired,
+	}).Return(nil)
+
+	NewBindingExpiration(mockChannelService, mockContactService, mockBindingService, metricService).ExpireBindings()
+}
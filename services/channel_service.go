@@ -2,6 +2,12 @@ package services
 
 import (
 	"context"
+	"encoding/base64"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/weni/whatsapp-router/logger"
 	"github.com/weni/whatsapp-router/metric"
@@ -9,23 +15,63 @@ import (
 	"github.com/weni/whatsapp-router/repositories"
 	"github.com/weni/whatsapp-router/servers/grpc/pb"
 	"github.com/weni/whatsapp-router/utils"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+	// maxTokenAttempts limits the tokens generated when they collide with
+	// the token of another channel.
+	maxTokenAttempts = 5
+)
+
+var errTokenAttempts = errors.New("could not generate an unique channel token")
+
+var (
+	ErrAccountNotFound = errors.New("whatsapp account not found")
+	ErrAccountMismatch = errors.New("channels belong to different whatsapp accounts")
+	// ErrEmptyChannelUpdate is returned by handlers of channel updates without
+	// any field to update.
+	ErrEmptyChannelUpdate = errors.New("channel name, welcome message or binding expiration must be given")
 )
 
 type ChannelService interface {
 	FindChannel(*models.Channel) (*models.Channel, error)
 	FindChannelById(string) (*models.Channel, error)
 	FindChannelByToken(string) (*models.Channel, error)
+	FindChannelByCredential(string) (*models.Channel, error)
 	CreateChannel(context.Context, *pb.ChannelRequest) (*pb.ChannelResponse, error)
+	GetChannel(context.Context, *pb.GetChannelRequest) (*pb.Channel, error)
+	ListChannels(context.Context, *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error)
+	UpdateChannel(context.Context, *pb.UpdateChannelRequest) (*pb.Channel, error)
+	DeleteChannel(context.Context, *pb.DeleteChannelRequest) (*pb.DeleteChannelResponse, error)
+	RotateChannelToken(context.Context, *pb.RotateChannelTokenRequest) (*pb.ChannelResponse, error)
+	RotateChannelCredential(context.Context, *pb.RotateChannelCredentialRequest) (*pb.ChannelResponse, error)
 	CreateChannelDefault(*models.Channel) (*models.Channel, error)
+	ListChannelsDefault(int64, int64) ([]*models.Channel, error)
+	UpdateChannelDefault(*models.Channel) (*models.Channel, error)
+	DeleteChannelDefault(string, string) error
+	RotateChannelTokenDefault(string) (*models.Channel, error)
+	RotateChannelCredentialDefault(string) (*models.Channel, error)
 }
 
 type DefaultChannelService struct {
-	repo    repositories.ChannelRepository
-	Metrics *metric.Service
+	repo        repositories.ChannelRepository
+	contactRepo repositories.ContactRepository
+	accountRepo repositories.WhatsappAccountRepository
+	bindingRepo repositories.ContactBindingRepository
+	Metrics     *metric.Service
 }
 
 func (s DefaultChannelService) FindChannel(req *models.Channel) (*models.Channel, error) {
-	return nil, nil
+	ch, err := s.repo.FindOne(req)
+	if err != nil {
+		return nil, err
+	}
+	return ch, nil
 }
 
 func (s DefaultChannelService) FindChannelById(req string) (*models.Channel, error) {
@@ -44,35 +90,429 @@ func (s DefaultChannelService) FindChannelByToken(req string) (*models.Channel,
 	return ch, nil
 }
 
+// FindChannelByCredential returns the channel authenticated by the credential,
+// or repositories.ErrNotFound if there is none.
+func (s DefaultChannelService) FindChannelByCredential(credential string) (*models.Channel, error) {
+	if credential == "" {
+		return nil, repositories.ErrNotFound
+	}
+	return s.repo.FindByCredential(credential)
+}
+
 func (s DefaultChannelService) CreateChannel(ctx context.Context, req *pb.ChannelRequest) (*pb.ChannelResponse, error) {
-	var channel models.Channel
-	channel.UUID = req.GetUuid()
-	channel.Name = req.GetName()
-	token := utils.GenToken()
-	channel.Token = token
-	err := s.repo.Insert(&channel)
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel uuid could not be empty")
+	}
+	channel, err := s.CreateChannelDefault(&models.Channel{
+		UUID:              req.GetUuid(),
+		Name:              req.GetName(),
+		WelcomeMessage:    req.GetWelcomeMessage(),
+		WelcomeMessages:   fromPbWelcomeMessages(req.GetWelcomeMessages()),
+		Account:           req.GetAccount(),
+		BindingTTL:        req.GetBindingTtl(),
+		InactivityTimeout: req.GetInactivityTimeout(),
+	})
 	if err != nil {
-		logger.Error(err.Error())
-		return nil, err
+		return nil, channelStatusError(err)
+	}
+	return &pb.ChannelResponse{
+		Token:      channel.Token,
+		Credential: channel.Credential,
+	}, nil
+}
+
+func (s DefaultChannelService) GetChannel(ctx context.Context, req *pb.GetChannelRequest) (*pb.Channel, error) {
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel uuid could not be empty")
+	}
+	ch, err := s.FindChannel(&models.Channel{UUID: req.GetUuid()})
+	if err != nil {
+		return nil, channelStatusError(err)
+	}
+	return toPbChannel(ch), nil
+}
+
+// ListChannels returns a page of channels. The page token is an opaque
+// encoding of the offset of the page, returned as next_page_token while
+// there are more channels to list.
+func (s DefaultChannelService) ListChannels(ctx context.Context, req *pb.ListChannelsRequest) (*pb.ListChannelsResponse, error) {
+	pageSize := int64(req.GetPageSize())
+	if pageSize == 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize < 0 || pageSize > maxPageSize {
+		return nil, status.Errorf(codes.InvalidArgument, "page size must be between 1 and %d", maxPageSize)
+	}
+	offset, err := decodePageToken(req.GetPageToken())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, "invalid page token")
+	}
+
+	channels, err := s.repo.FindAll(offset, pageSize+1)
+	if err != nil {
+		return nil, channelStatusError(err)
+	}
+	res := &pb.ListChannelsResponse{}
+	if int64(len(channels)) > pageSize {
+		channels = channels[:pageSize]
+		res.NextPageToken = encodePageToken(offset + pageSize)
+	}
+	for _, ch := range channels {
+		res.Channels = append(res.Channels, toPbChannel(ch))
+	}
+	return res, nil
+}
+
+func (s DefaultChannelService) UpdateChannel(ctx context.Context, req *pb.UpdateChannelRequest) (*pb.Channel, error) {
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel uuid could not be empty")
+	}
+	ch := &models.Channel{
+		UUID:              req.GetUuid(),
+		Name:              req.GetName(),
+		WelcomeMessage:    req.GetWelcomeMessage(),
+		WelcomeMessages:   fromPbWelcomeMessages(req.GetWelcomeMessages()),
+		BindingTTL:        req.GetBindingTtl(),
+		InactivityTimeout: req.GetInactivityTimeout(),
+	}
+	if IsEmptyChannelUpdate(ch) {
+		return nil, status.Error(codes.InvalidArgument, ErrEmptyChannelUpdate.Error())
+	}
+	ch, err := s.UpdateChannelDefault(ch)
+	if err != nil {
+		return nil, channelStatusError(err)
+	}
+	return toPbChannel(ch), nil
+}
+
+func (s DefaultChannelService) DeleteChannel(ctx context.Context, req *pb.DeleteChannelRequest) (*pb.DeleteChannelResponse, error) {
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel uuid could not be empty")
+	}
+	if req.GetReassignTo() == req.GetUuid() {
+		return nil, status.Error(codes.InvalidArgument, "channel could not be reassigned to itself")
+	}
+	if err := s.DeleteChannelDefault(req.GetUuid(), req.GetReassignTo()); err != nil {
+		return nil, channelStatusError(err)
+	}
+	return &pb.DeleteChannelResponse{}, nil
+}
+
+func (s DefaultChannelService) RotateChannelToken(ctx context.Context, req *pb.RotateChannelTokenRequest) (*pb.ChannelResponse, error) {
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel uuid could not be empty")
+	}
+	ch, err := s.RotateChannelTokenDefault(req.GetUuid())
+	if err != nil {
+		return nil, channelStatusError(err)
 	}
-	channelCreationMetric := metric.NewChannelCreation(channel.UUID)
-	s.Metrics.SaveChannelCreation(channelCreationMetric)
 	return &pb.ChannelResponse{
-		Token: channel.Token,
+		Token: ch.Token,
 	}, nil
 }
 
+func (s DefaultChannelService) RotateChannelCredential(ctx context.Context, req *pb.RotateChannelCredentialRequest) (*pb.ChannelResponse, error) {
+	if req.GetUuid() == "" {
+		return nil, status.Error(codes.InvalidArgument, "channel uuid could not be empty")
+	}
+	ch, err := s.RotateChannelCredentialDefault(req.GetUuid())
+	if err != nil {
+		return nil, channelStatusError(err)
+	}
+	return &pb.ChannelResponse{
+		Token:      ch.Token,
+		Credential: ch.Credential,
+	}, nil
+}
+
+// CreateChannelDefault creates the channel with a new token and credential.
+// Creation is idempotent: if a channel with the same uuid exists it is
+// returned instead, without its credential, unless one is issued because it
+// was created without one. The credential is only returned when generated.
 func (s DefaultChannelService) CreateChannelDefault(channel *models.Channel) (*models.Channel, error) {
-	err := s.repo.Insert(channel)
+	existing, err := s.repo.FindOne(channel)
+	if err == nil {
+		if existing.Credential == "" {
+			return s.RotateChannelCredentialDefault(existing.UUID)
+		}
+		existing.Credential = ""
+		return existing, nil
+	}
+	if !errors.Is(err, repositories.ErrNotFound) {
+		logger.Error(err.Error())
+		return nil, err
+	}
+
+	if channel.Account != "" {
+		if _, err := s.accountRepo.FindByName(channel.Account); err != nil {
+			if errors.Is(err, repositories.ErrNotFound) {
+				return nil, ErrAccountNotFound
+			}
+			logger.Error(err.Error())
+			return nil, err
+		}
+	}
+	channel.WelcomeMessages = normalizeWelcomeMessages(channel.WelcomeMessages)
+	channel.BindingTTL = nonNegative(channel.BindingTTL)
+	channel.InactivityTimeout = nonNegative(channel.InactivityTimeout)
+	channel.Credential = utils.GenCredential()
+	for i := 0; i < maxTokenAttempts; i++ {
+		channel.Token = utils.GenToken()
+		err := s.repo.Insert(channel)
+		if err == nil {
+			channelCreationMetric := metric.NewChannelCreation(channel.UUID)
+			s.Metrics.SaveChannelCreation(channelCreationMetric)
+			return channel, nil
+		}
+		if !errors.Is(err, repositories.ErrDuplicate) {
+			logger.Error(err.Error())
+			return nil, err
+		}
+		// the channel may have been created concurrently, otherwise the
+		// generated token is already in use and a new one is generated.
+		if existing, err := s.repo.FindOne(channel); err == nil {
+			existing.Credential = ""
+			return existing, nil
+		}
+	}
+	logger.Error(errTokenAttempts.Error())
+	return nil, errTokenAttempts
+}
+
+func (s DefaultChannelService) ListChannelsDefault(page int64, limit int64) ([]*models.Channel, error) {
+	return s.repo.FindAll((page-1)*limit, limit)
+}
+
+// UpdateChannelDefault updates the name, welcome messages and binding
+// expiration of the channel with the uuid of req, keeping the fields that are
+// empty in req. Negative binding expirations disable the expiration.
+func (s DefaultChannelService) UpdateChannelDefault(req *models.Channel) (*models.Channel, error) {
+	ch, err := s.repo.FindOne(req)
 	if err != nil {
+		return nil, err
+	}
+	if req.Name != "" {
+		ch.Name = req.Name
+	}
+	if req.WelcomeMessage != "" {
+		ch.WelcomeMessage = req.WelcomeMessage
+	}
+	if len(req.WelcomeMessages) > 0 {
+		ch.WelcomeMessages = normalizeWelcomeMessages(req.WelcomeMessages)
+	}
+	if req.BindingTTL != 0 {
+		ch.BindingTTL = nonNegative(req.BindingTTL)
+	}
+	if req.InactivityTimeout != 0 {
+		ch.InactivityTimeout = nonNegative(req.InactivityTimeout)
+	}
+	if err := s.repo.Update(ch); err != nil {
 		logger.Error(err.Error())
 		return nil, err
 	}
-	channelCreationMetric := metric.NewChannelCreation(channel.UUID)
-	s.Metrics.SaveChannelCreation(channelCreationMetric)
-	return channel, nil
+	return ch, nil
+}
+
+// DeleteChannelDefault deletes the channel with the given uuid. Contacts bound
+// to it are reassigned to the channel with reassignTo uuid, or unbound if
+// reassignTo is empty.
+func (s DefaultChannelService) DeleteChannelDefault(uuid string, reassignTo string) error {
+	ch, err := s.repo.FindOne(&models.Channel{UUID: uuid})
+	if err != nil {
+		return err
+	}
+	var target *models.Channel
+	targetID := primitive.NilObjectID
+	if reassignTo != "" {
+		target, err = s.repo.FindOne(&models.Channel{UUID: reassignTo})
+		if err != nil {
+			return err
+		}
+		if target.Account != ch.Account {
+			return ErrAccountMismatch
+		}
+		targetID = target.ID
+	}
+
+	contacts, err := s.contactRepo.FindAll(&models.Contact{Account: ch.Account, Channel: ch.ID}, 0, 0)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+	reassigned, err := s.contactRepo.ReassignChannel(ch.ID, targetID)
+	if err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+	s.recordChannelDelete(contacts, ch.ID, targetID)
+	if err := s.repo.Delete(ch.ID.Hex()); err != nil {
+		logger.Error(err.Error())
+		return err
+	}
+
+	for i := int64(0); i < reassigned; i++ {
+		s.Metrics.DecContactActivated(metric.NewContactActivated(ch.UUID))
+		if target != nil {
+			s.Metrics.IncContactActivated(metric.NewContactActivated(target.UUID))
+		}
+	}
+	return nil
+}
+
+// recordChannelDelete appends to the binding history of the contacts of the
+// deleted channel their reassignment to the target channel, or their unbinding
+// if there is no target.
+func (s DefaultChannelService) recordChannelDelete(contacts []*models.Contact, from primitive.ObjectID, to primitive.ObjectID) {
+	now := time.Now().UTC()
+	for _, contact := range contacts {
+		binding := &models.ContactBinding{
+			URN:             contact.URN,
+			Account:         contact.Account,
+			Event:           models.BindingUnbind,
+			PreviousChannel: from,
+			Reason:          models.BindingReasonChannelDelete,
+			CreatedAt:       now,
+		}
+		if !to.IsZero() {
+			binding.Event = models.BindingBind
+			binding.Channel = to
+		}
+		if err := s.bindingRepo.Insert(binding); err != nil {
+			logger.Error(err.Error())
+		}
+	}
+}
+
+func (s DefaultChannelService) RotateChannelTokenDefault(uuid string) (*models.Channel, error) {
+	ch, err := s.repo.FindOne(&models.Channel{UUID: uuid})
+	if err != nil {
+		return nil, err
+	}
+	for i := 0; i < maxTokenAttempts; i++ {
+		ch.Token = utils.GenToken()
+		err := s.repo.Update(ch)
+		if err == nil {
+			return ch, nil
+		}
+		if !errors.Is(err, repositories.ErrDuplicate) {
+			logger.Error(err.Error())
+			return nil, err
+		}
+	}
+	logger.Error(errTokenAttempts.Error())
+	return nil, errTokenAttempts
+}
+
+// RotateChannelCredentialDefault issues a new credential to the channel, the
+// previous one is no longer accepted.
+func (s DefaultChannelService) RotateChannelCredentialDefault(uuid string) (*models.Channel, error) {
+	ch, err := s.repo.FindOne(&models.Channel{UUID: uuid})
+	if err != nil {
+		return nil, err
+	}
+	ch.Credential = utils.GenCredential()
+	if err := s.repo.Update(ch); err != nil {
+		logger.Error(err.Error())
+		return nil, err
+	}
+	return ch, nil
+}
+
+// channelStatusError converts repository errors to grpc status errors, so
+// database errors are not exposed to clients.
+func channelStatusError(err error) error {
+	switch {
+	case errors.Is(err, repositories.ErrNotFound):
+		return status.Error(codes.NotFound, "channel not found")
+	case errors.Is(err, repositories.ErrDuplicate):
+		return status.Error(codes.AlreadyExists, "channel already exists")
+	case errors.Is(err, ErrAccountNotFound), errors.Is(err, ErrAccountMismatch):
+		return status.Error(codes.InvalidArgument, err.Error())
+	default:
+		logger.Error(err.Error())
+		return status.Error(codes.Internal, "internal error")
+	}
+}
+
+func toPbChannel(ch *models.Channel) *pb.Channel {
+	pbChannel := &pb.Channel{
+		Uuid:              ch.UUID,
+		Name:              ch.Name,
+		Token:             ch.Token,
+		WelcomeMessage:    ch.WelcomeMessage,
+		Account:           ch.Account,
+		BindingTtl:        ch.BindingTTL,
+		InactivityTimeout: ch.InactivityTimeout,
+	}
+	for language, text := range ch.WelcomeMessages {
+		pbChannel.WelcomeMessages = append(pbChannel.WelcomeMessages, &pb.WelcomeMessage{
+			Language: language,
+			Text:     text,
+		})
+	}
+	sort.Slice(pbChannel.WelcomeMessages, func(i, j int) bool {
+		return pbChannel.WelcomeMessages[i].Language < pbChannel.WelcomeMessages[j].Language
+	})
+	return pbChannel
+}
+
+// IsEmptyChannelUpdate reports whether the update of the channel has none of
+// the fields that can be updated.
+func IsEmptyChannelUpdate(ch *models.Channel) bool {
+	return ch.Name == "" && ch.WelcomeMessage == "" && len(ch.WelcomeMessages) == 0 &&
+		ch.BindingTTL == 0 && ch.InactivityTimeout == 0
+}
+
+func nonNegative(n int64) int64 {
+	if n < 0 {
+		return 0
+	}
+	return n
+}
+
+// normalizeWelcomeMessages lower cases the languages of the welcome messages,
+// as they are matched with the language of the contact phone number.
+func normalizeWelcomeMessages(messages map[string]string) map[string]string {
+	if len(messages) == 0 {
+		return nil
+	}
+	normalized := map[string]string{}
+	for language, text := range messages {
+		normalized[strings.ToLower(strings.TrimSpace(language))] = text
+	}
+	return normalized
+}
+
+func fromPbWelcomeMessages(messages []*pb.WelcomeMessage) map[string]string {
+	if len(messages) == 0 {
+		return nil
+	}
+	welcomeMessages := map[string]string{}
+	for _, m := range messages {
+		welcomeMessages[m.GetLanguage()] = m.GetText()
+	}
+	return welcomeMessages
+}
+
+func encodePageToken(offset int64) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(strconv.FormatInt(offset, 10)))
+}
+
+func decodePageToken(token string) (int64, error) {
+	if token == "" {
+		return 0, nil
+	}
+	b, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return 0, err
+	}
+	offset, err := strconv.ParseInt(string(b), 10, 64)
+	if err != nil || offset < 0 {
+		return 0, errors.New("invalid page token")
+	}
+	return offset, nil
 }
 
-func NewChannelService(repo repositories.ChannelRepository, metricService *metric.Service) DefaultChannelService {
-	return DefaultChannelService{repo, metricService}
+func NewChannelService(repo repositories.ChannelRepository, contactRepo repositories.ContactRepository, accountRepo repositories.WhatsappAccountRepository, bindingRepo repositories.ContactBindingRepository, metricService *metric.Service) DefaultChannelService {
+	return DefaultChannelService{repo, contactRepo, accountRepo, bindingRepo, metricService}
 }
@@ -0,0 +1,63 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/metric"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/servers/grpc/pb"
+)
+
+// fakeChannelRepository keeps the channels by uuid in memory.
+type fakeChannelRepository struct {
+	repositories.ChannelRepository
+	channels map[string]models.Channel
+}
+
+func (f *fakeChannelRepository) Insert(ch *models.Channel) error {
+	if _, ok := f.channels[ch.UUID]; ok {
+		return repositories.ErrDuplicate
+	}
+	f.channels[ch.UUID] = *ch
+	return nil
+}
+
+func (f *fakeChannelRepository) FindOne(ch *models.Channel) (*models.Channel, error) {
+	found, ok := f.channels[ch.UUID]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &found, nil
+}
+
+func (f *fakeChannelRepository) Update(ch *models.Channel) error {
+	f.channels[ch.UUID] = *ch
+	return nil
+}
+
+func TestCreateChannelTwice(t *testing.T) {
+	metricService, err := metric.NewPrometheusService()
+	assert.NoError(t, err)
+	repo := &fakeChannelRepository{channels: map[string]models.Channel{}}
+	s := NewChannelService(repo, nil, nil, nil, metricService)
+
+	created, err := s.CreateChannel(context.Background(), &pb.ChannelRequest{Uuid: "425b41f0-c554-4943-989c-5f88561a0cf5"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, created.Credential)
+
+	// creating the channel again does not return its credential
+	again, err := s.CreateChannel(context.Background(), &pb.ChannelRequest{Uuid: "425b41f0-c554-4943-989c-5f88561a0cf5"})
+	assert.NoError(t, err)
+	assert.Equal(t, created.Token, again.Token)
+	assert.Empty(t, again.Credential)
+	assert.Equal(t, created.Credential, repo.channels["425b41f0-c554-4943-989c-5f88561a0cf5"].Credential)
+
+	// channels created before credentials get one
+	repo.channels["0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1"] = models.Channel{UUID: "0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1", Token: "weni-demo-0123456789"}
+	legacy, err := s.CreateChannelDefault(&models.Channel{UUID: "0b2e0a8a-0c4f-4f55-9a1e-2b8f0a37d5c1"})
+	assert.NoError(t, err)
+	assert.NotEmpty(t, legacy.Credential)
+}
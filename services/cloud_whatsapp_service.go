@@ -0,0 +1,203 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/utils"
+)
+
+const messagingProduct = "whatsapp"
+
+var ErrLoginNotSupported = errors.New("whatsapp cloud api accounts use a permanent access token")
+
+var ErrTemplatesNotSupported = errors.New("whatsapp cloud api templates are managed in the business account")
+
+// CloudWhatsappService sends the requests of the on-premises business api
+// format, the one courier speaks, to the whatsapp cloud api.
+type CloudWhatsappService struct {
+	account *models.WhatsappAccount
+}
+
+func NewCloudWhatsappService(account *models.WhatsappAccount) CloudWhatsappService {
+	return CloudWhatsappService{account}
+}
+
+// SendMessage sends the message from the phone number of the account, the
+// cloud api responds with the message ids in the same format as the
+// on-premises api.
+func (ws CloudWhatsappService) SendMessage(body []byte) (http.Header, io.ReadCloser, error) {
+	cloudBody, err := cloudMessage(body)
+	if err != nil {
+		return nil, nil, err
+	}
+	req, err := http.NewRequest(
+		"POST",
+		ws.account.BaseURL+"/"+ws.account.PhoneNumberID+"/messages",
+		bytes.NewReader(cloudBody),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	res, err := ws.do(req)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.StatusCode == 401 {
+		res.Body.Close()
+		return nil, nil, errors.New(res.Status)
+	}
+	return res.Header, res.Body, nil
+}
+
+func (ws CloudWhatsappService) Login() (*http.Response, error) {
+	return nil, ErrLoginNotSupported
+}
+
+func (ws CloudWhatsappService) Account() *models.WhatsappAccount {
+	return ws.account
+}
+
+// Health requests the phone number of the account, the cloud api has no health
+// endpoint.
+func (ws CloudWhatsappService) Health() (*http.Response, error) {
+	req, err := http.NewRequest("GET", ws.account.BaseURL+"/"+ws.account.PhoneNumberID, nil)
+	if err != nil {
+		return nil, err
+	}
+	return ws.do(req)
+}
+
+// GetMedia looks up the url of the media and downloads it, as the on-premises
+// api responds the media itself.
+func (ws CloudWhatsappService) GetMedia(header http.Header, mediaID string) (*http.Response, error) {
+	req, err := http.NewRequest("GET", ws.account.BaseURL+"/"+mediaID, nil)
+	if err != nil {
+		return nil, err
+	}
+	res, err := ws.do(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+	defer res.Body.Close()
+	var media struct {
+		URL string `json:"url"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&media); err != nil {
+		return nil, err
+	}
+	if media.URL == "" {
+		return nil, fmt.Errorf("whatsapp cloud api responded without url to media %s", mediaID)
+	}
+
+	req, err = http.NewRequest("GET", media.URL, nil)
+	if err != nil {
+		return nil, err
+	}
+	utils.CopyHeader(req.Header, header)
+	return ws.do(req)
+}
+
+// PostMedia uploads the media in the request body, with the type of its
+// Content-Type header, responding the media id in the on-premises format.
+func (ws CloudWhatsappService) PostMedia(header http.Header, body io.ReadCloser) (*http.Response, error) {
+	defer body.Close()
+	var form bytes.Buffer
+	writer := multipart.NewWriter(&form)
+	if err := writer.WriteField("messaging_product", messagingProduct); err != nil {
+		return nil, err
+	}
+	contentType := header.Get("Content-Type")
+	if err := writer.WriteField("type", contentType); err != nil {
+		return nil, err
+	}
+	part, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Disposition": {`form-data; name="file"; filename="file"`},
+		"Content-Type":        {contentType},
+	})
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.Copy(part, body); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest("POST", ws.account.BaseURL+"/"+ws.account.PhoneNumberID+"/media", &form)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	res, err := ws.do(req)
+	if err != nil || res.StatusCode != http.StatusOK {
+		return res, err
+	}
+
+	var uploaded struct {
+		ID string `json:"id"`
+	}
+	err = json.NewDecoder(res.Body).Decode(&uploaded)
+	res.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	media, err := json.Marshal(map[string][]map[string]string{
+		"media": {{"id": uploaded.ID}},
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.StatusCode = http.StatusCreated
+	res.Status = http.StatusText(http.StatusCreated)
+	res.Header.Del("Content-Length")
+	res.ContentLength = int64(len(media))
+	res.Body = io.NopCloser(bytes.NewReader(media))
+	return res, nil
+}
+
+// GetTemplates returns ErrTemplatesNotSupported, as the templates of the cloud
+// api belong to the business account and not to the phone number.
+func (ws CloudWhatsappService) GetTemplates() (*http.Response, error) {
+	return nil, ErrTemplatesNotSupported
+}
+
+func (ws CloudWhatsappService) do(req *http.Request) (*http.Response, error) {
+	req.Header.Set("Authorization", "Bearer "+ws.account.AccessToken)
+	return utils.GetHTTPClient().Do(req)
+}
+
+// cloudMessage converts a message of the on-premises api to the cloud api,
+// which requires the messaging product, has the link preview flag in the text
+// and no namespace or language policy in templates.
+func cloudMessage(body []byte) ([]byte, error) {
+	var msg map[string]interface{}
+	if err := json.Unmarshal(body, &msg); err != nil {
+		return nil, err
+	}
+	msg["messaging_product"] = messagingProduct
+
+	if previewURL, ok := msg["preview_url"]; ok {
+		delete(msg, "preview_url")
+		if text, ok := msg["text"].(map[string]interface{}); ok {
+			text["preview_url"] = previewURL
+		}
+	}
+	if template, ok := msg["template"].(map[string]interface{}); ok {
+		delete(template, "namespace")
+		if language, ok := template["language"].(map[string]interface{}); ok {
+			delete(language, "policy")
+		}
+	}
+	return json.Marshal(msg)
+}
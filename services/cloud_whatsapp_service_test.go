@@ -0,0 +1,87 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+)
+
+func newTestCloudAccount(baseURL string) *models.WhatsappAccount {
+	return &models.WhatsappAccount{
+		Provider:      models.ProviderCloud,
+		BaseURL:       baseURL,
+		PhoneNumberID: "106540352242922",
+		AccessToken:   "permanent-token",
+	}
+}
+
+func TestCloudSendMessage(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/106540352242922/messages", r.URL.Path)
+		assert.Equal(t, "Bearer permanent-token", r.Header.Get("Authorization"))
+		body, _ := io.ReadAll(r.Body)
+		assert.JSONEq(t, `{
+			"messaging_product": "whatsapp",
+			"to": "5582988887777",
+			"type": "text",
+			"text": {"body": "https://weni.ai", "preview_url": true}
+		}`, string(body))
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"messaging_product":"whatsapp","contacts":[{"input":"5582988887777","wa_id":"5582988887777"}],"messages":[{"id":"wamid.HBgLNTU4Mjk4ODg4Nzc3NxUCABEYEjQ2"}]}`))
+	}))
+	defer server.Close()
+
+	ws := NewCloudWhatsappService(newTestCloudAccount(server.URL))
+	_, body, err := ws.SendMessage([]byte(`{"to":"5582988887777","type":"text","preview_url":true,"text":{"body":"https://weni.ai"}}`))
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(body)
+	body.Close()
+	assert.Contains(t, string(b), `"messages":[{"id":"wamid.HBgLNTU4Mjk4ODg4Nzc3NxUCABEYEjQ2"}]`)
+}
+
+func TestCloudMedia(t *testing.T) {
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer permanent-token", r.Header.Get("Authorization"))
+		switch r.Method + " " + r.URL.Path {
+		case "GET /1037543291543636":
+			w.Write([]byte(`{"url":"` + server.URL + `/download/1037543291543636","mime_type":"image/jpeg","id":"1037543291543636"}`))
+		case "GET /download/1037543291543636":
+			w.Header().Set("Content-Type", "image/jpeg")
+			w.Write([]byte("jpeg"))
+		case "POST /106540352242922/media":
+			assert.Equal(t, "whatsapp", r.FormValue("messaging_product"))
+			assert.Equal(t, "image/jpeg", r.FormValue("type"))
+			file, _, err := r.FormFile("file")
+			assert.NoError(t, err)
+			b, _ := io.ReadAll(file)
+			assert.Equal(t, "jpeg", string(b))
+			w.Write([]byte(`{"id":"1037543291543636"}`))
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ws := NewCloudWhatsappService(newTestCloudAccount(server.URL))
+
+	res, err := ws.GetMedia(http.Header{}, "1037543291543636")
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+	assert.Equal(t, "jpeg", string(b))
+
+	res, err = ws.PostMedia(http.Header{"Content-Type": {"image/jpeg"}}, io.NopCloser(strings.NewReader("jpeg")))
+	assert.NoError(t, err)
+	b, _ = io.ReadAll(res.Body)
+	res.Body.Close()
+	assert.Equal(t, http.StatusCreated, res.StatusCode)
+	assert.JSONEq(t, `{"media":[{"id":"1037543291543636"}]}`, string(b))
+}
@@ -22,7 +22,8 @@ func (s *configService) CreateOrUpdate(conf *models.Config) (*models.Config, err
 	cf, _ := s.repo.GetFirst()
 	if cf == nil {
 		c := &models.Config{
-			Token: conf.Token,
+			Token:     conf.Token,
+			ExpiresAt: conf.ExpiresAt,
 		}
 		err := s.repo.Create(c)
 		if err != nil {
@@ -31,6 +32,7 @@ func (s *configService) CreateOrUpdate(conf *models.Config) (*models.Config, err
 		return s.repo.GetFirst()
 	} else {
 		cf.Token = conf.Token
+		cf.ExpiresAt = conf.ExpiresAt
 		return s.repo.Update(cf)
 	}
 }
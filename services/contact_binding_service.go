@@ -0,0 +1,36 @@
+package services
+
+import (
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+// ContactBindingService records the history of the channels contacts are bound
+// to.
+type ContactBindingService interface {
+	RecordBinding(*models.ContactBinding) error
+	ListBindings(urn string, account string, page int64, limit int64) ([]*models.ContactBinding, error)
+}
+
+type DefaultContactBindingService struct {
+	repo repositories.ContactBindingRepository
+}
+
+// RecordBinding appends the change of channel of the contact to its history,
+// at the current time.
+func (s DefaultContactBindingService) RecordBinding(binding *models.ContactBinding) error {
+	binding.CreatedAt = time.Now().UTC()
+	return s.repo.Insert(binding)
+}
+
+// ListBindings returns a page of the binding history of the contact of the
+// account, most recent first.
+func (s DefaultContactBindingService) ListBindings(urn string, account string, page int64, limit int64) ([]*models.ContactBinding, error) {
+	return s.repo.FindByContact(urn, account, (page-1)*limit, limit)
+}
+
+func NewContactBindingService(repo repositories.ContactBindingRepository) DefaultContactBindingService {
+	return DefaultContactBindingService{repo}
+}
@@ -1,14 +1,21 @@
 package services
 
 import (
+	"time"
+
 	"github.com/weni/whatsapp-router/models"
 	"github.com/weni/whatsapp-router/repositories"
 )
 
 type ContactService interface {
 	FindContact(*models.Contact) (*models.Contact, error)
+	ListContacts(filter *models.Contact, page int64, limit int64) ([]*models.Contact, error)
 	CreateContact(*models.Contact) (*models.Contact, error)
 	UpdateContact(*models.Contact) (*models.Contact, error)
+	UnbindContact(*models.Contact) (*models.Contact, error)
+	RecordInbound(*models.Contact, time.Time) error
+	ExpireBindings(*models.Channel, time.Time) ([]*models.Contact, error)
+	ClearBindingExpired(*models.Contact) error
 }
 
 type DefaultContactService struct {
@@ -23,11 +30,21 @@ func (s DefaultContactService) FindContact(req *models.Contact) (*models.Contact
 	return c, nil
 }
 
+// ListContacts returns a page of the contacts of the account of the filter,
+// bound to its channel if it has one.
+func (s DefaultContactService) ListContacts(filter *models.Contact, page int64, limit int64) ([]*models.Contact, error) {
+	return s.repo.FindAll(filter, (page-1)*limit, limit)
+}
+
 func (s DefaultContactService) CreateContact(req *models.Contact) (*models.Contact, error) {
 	c := &models.Contact{
 		URN:     req.URN,
 		Name:    req.Name,
 		Channel: req.Channel,
+		Account: req.Account,
+	}
+	if !c.Channel.IsZero() {
+		c.BoundAt = time.Now().UTC()
 	}
 
 	newContact, err := s.repo.Insert(c)
@@ -42,6 +59,10 @@ func (s DefaultContactService) UpdateContact(req *models.Contact) (*models.Conta
 		URN:     req.URN,
 		Name:    req.Name,
 		Channel: req.Channel,
+		Account: req.Account,
+	}
+	if !c.Channel.IsZero() {
+		c.BoundAt = time.Now().UTC()
 	}
 	updatedContact, err := s.repo.Update(c)
 	if err != nil {
@@ -50,6 +71,39 @@ func (s DefaultContactService) UpdateContact(req *models.Contact) (*models.Conta
 	return updatedContact, nil
 }
 
+// UnbindContact removes the channel the contact is bound to, so its messages
+// are no longer redirected until a new token is sent.
+func (s DefaultContactService) UnbindContact(req *models.Contact) (*models.Contact, error) {
+	c := &models.Contact{
+		URN:     req.URN,
+		Name:    req.Name,
+		Account: req.Account,
+	}
+	if err := s.repo.UnsetChannel(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// RecordInbound records a message received from the contact at the given
+// time, opening its customer service window.
+func (s DefaultContactService) RecordInbound(req *models.Contact, at time.Time) error {
+	return s.repo.SetLastInbound(&models.Contact{URN: req.URN, Account: req.Account}, at.UTC())
+}
+
+// ExpireBindings unbinds the contacts whose binding to the channel expired at
+// the given time, by its binding TTL or inactivity timeout, and returns them.
+func (s DefaultContactService) ExpireBindings(channel *models.Channel, now time.Time) ([]*models.Contact, error) {
+	boundBefore, inactiveBefore := channel.BindingExpiration(now.UTC())
+	return s.repo.ExpireBindings(channel.ID, boundBefore, inactiveBefore)
+}
+
+// ClearBindingExpired unmarks the expired binding of the contact, once the
+// contact was told about it.
+func (s DefaultContactService) ClearBindingExpired(req *models.Contact) error {
+	return s.repo.ClearBindingExpired(req)
+}
+
 func NewContactService(repo repositories.ContactRepository) DefaultContactService {
 	return DefaultContactService{repo}
 }
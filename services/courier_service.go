@@ -3,33 +3,186 @@ package services
 import (
 	"bytes"
 	"fmt"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/utils"
 )
 
+// forwardLease is how long a forward being retried is hidden from other workers.
+const forwardLease = time.Minute
+
 type CourierService interface {
 	RedirectMessage(string, string) (int, error)
+	RetryPendingMessages()
+	ListDeadLetters(int64, int64) ([]*models.Forward, error)
+	ReplayDeadLetter(string) (*models.Forward, error)
 }
 
 type DefaultCourierService struct {
+	repo  repositories.ForwardRepository
+	retry config.CourierRetry
 }
 
+// RedirectMessage posts the message to the courier of the channel. When
+// courier is unavailable the message is queued to be retried later and
+// http.StatusAccepted is returned, when courier rejects it the message is
+// moved to the dead letter collection.
 func (cs DefaultCourierService) RedirectMessage(channelUUID string, msg string) (int, error) {
+	status, err := cs.post(channelUUID, msg)
+	if err == nil && status < 400 {
+		return status, nil
+	}
+
+	now := time.Now()
+	forward := &models.Forward{
+		ChannelUUID: channelUUID,
+		Payload:     msg,
+		Attempts:    1,
+		LastError:   forwardError(status, err),
+		NextAttempt: now.Add(cs.backoff(1)),
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+	if !shouldRetry(status, err) {
+		if err := cs.repo.InsertDeadLetter(forward); err != nil {
+			return http.StatusInternalServerError, err
+		}
+		logger.Error(fmt.Sprintf("message for channel %s dead lettered: %s", channelUUID, forward.LastError))
+		return status, nil
+	}
+	if err := cs.repo.Insert(forward); err != nil {
+		return http.StatusInternalServerError, err
+	}
+	logger.Info(fmt.Sprintf("message for channel %s queued for retry: %s", channelUUID, forward.LastError))
+	return http.StatusAccepted, nil
+}
+
+// RetryPendingMessages retries every queued message that is due, moving to
+// the dead letter collection the ones that can not be delivered.
+func (cs DefaultCourierService) RetryPendingMessages() {
+	for {
+		forward, err := cs.repo.AcquireNext(time.Now(), forwardLease)
+		if err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		if forward == nil {
+			return
+		}
+		cs.retryForward(forward)
+	}
+}
+
+func (cs DefaultCourierService) ListDeadLetters(page int64, limit int64) ([]*models.Forward, error) {
+	return cs.repo.FindDeadLetters((page-1)*limit, limit)
+}
+
+// ReplayDeadLetter moves a dead letter back to the queue to be retried as soon
+// as possible.
+func (cs DefaultCourierService) ReplayDeadLetter(id string) (*models.Forward, error) {
+	forward, err := cs.repo.FindDeadLetterById(id)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	forward.Attempts = 0
+	forward.LastError = ""
+	forward.NextAttempt = now
+	forward.UpdatedAt = now
+	if err := cs.repo.Insert(forward); err != nil {
+		return nil, err
+	}
+	if err := cs.repo.DeleteDeadLetter(id); err != nil {
+		return nil, err
+	}
+	return forward, nil
+}
+
+func (cs DefaultCourierService) retryForward(forward *models.Forward) {
+	status, err := cs.post(forward.ChannelUUID, forward.Payload)
+	now := time.Now()
+	forward.Attempts++
+	forward.UpdatedAt = now
+
+	if err == nil && status < 400 {
+		if err := cs.repo.Delete(forward.ID.Hex()); err != nil {
+			logger.Error(err.Error())
+			return
+		}
+		logger.Info(fmt.Sprintf("queued message %s redirected with status %d for channel %s", forward.ID.Hex(), status, forward.ChannelUUID))
+		return
+	}
+
+	forward.LastError = forwardError(status, err)
+	if !shouldRetry(status, err) || forward.Attempts >= cs.retry.MaxAttempts {
+		cs.deadLetter(forward)
+		return
+	}
+	forward.NextAttempt = now.Add(cs.backoff(forward.Attempts))
+	if err := cs.repo.Update(forward); err != nil {
+		logger.Error(err.Error())
+	}
+}
+
+func (cs DefaultCourierService) deadLetter(forward *models.Forward) {
+	if err := cs.repo.InsertDeadLetter(forward); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	if err := cs.repo.Delete(forward.ID.Hex()); err != nil {
+		logger.Error(err.Error())
+		return
+	}
+	logger.Error(fmt.Sprintf("queued message %s for channel %s dead lettered after %d attempts: %s", forward.ID.Hex(), forward.ChannelUUID, forward.Attempts, forward.LastError))
+}
+
+// backoff returns the delay before the next attempt, doubling the base delay
+// on each attempt up to the max delay.
+func (cs DefaultCourierService) backoff(attempts int) time.Duration {
+	delay := cs.retry.BaseDelay
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= cs.retry.MaxDelay {
+			return cs.retry.MaxDelay
+		}
+	}
+	return delay
+}
+
+func (cs DefaultCourierService) post(channelUUID string, msg string) (int, error) {
 	courierBaseURL := config.GetConfig().App.CourierBaseURL
 	url := fmt.Sprintf("%v/%v/receive", courierBaseURL, channelUUID)
-	resp, err := http.Post(
+	resp, err := utils.GetHTTPClient().Post(
 		url,
 		"application/json",
 		bytes.NewBuffer([]byte(msg)))
 
 	if err != nil {
-		return resp.StatusCode, err
+		return 0, err
 	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
 
 	return resp.StatusCode, nil
 }
 
-func NewCourierService() DefaultCourierService {
-	return DefaultCourierService{}
+func shouldRetry(status int, err error) bool {
+	return err != nil || status == http.StatusTooManyRequests || status >= 500
+}
+
+func forwardError(status int, err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return fmt.Sprintf("courier responded with status %d", status)
+}
+
+func NewCourierService(repo repositories.ForwardRepository) DefaultCourierService {
+	return DefaultCourierService{repo, config.GetConfig().App.CourierRetry}
 }
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+// fakeForwardRepository records the queued and dead lettered forwards.
+type fakeForwardRepository struct {
+	repositories.ForwardRepository
+	queued      []*models.Forward
+	deadLetters []*models.Forward
+}
+
+func (f *fakeForwardRepository) Insert(forward *models.Forward) error {
+	f.queued = append(f.queued, forward)
+	return nil
+}
+
+func (f *fakeForwardRepository) InsertDeadLetter(forward *models.Forward) error {
+	f.deadLetters = append(f.deadLetters, forward)
+	return nil
+}
+
+func TestRedirectMessage(t *testing.T) {
+	var status int
+	courier := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/21ee95f6-3776-4b1e-aabc-742eb5dc9170/receive", r.URL.Path)
+		w.WriteHeader(status)
+	}))
+	defer courier.Close()
+	defer func(url string) { config.GetConfig().App.CourierBaseURL = url }(config.GetConfig().App.CourierBaseURL)
+	config.GetConfig().App.CourierBaseURL = courier.URL
+
+	tcs := []struct {
+		Label      string
+		Status     int
+		Redirected int
+		Queued     int
+		DeadLetter int
+	}{
+		{"delivered", http.StatusOK, http.StatusOK, 0, 0},
+		{"unavailable", http.StatusServiceUnavailable, http.StatusAccepted, 1, 0},
+		{"throttled", http.StatusTooManyRequests, http.StatusAccepted, 1, 0},
+		{"rejected", http.StatusBadRequest, http.StatusBadRequest, 0, 1},
+	}
+	for _, tc := range tcs {
+		t.Run(tc.Label, func(t *testing.T) {
+			status = tc.Status
+			repo := &fakeForwardRepository{}
+			cs := DefaultCourierService{repo, config.CourierRetry{BaseDelay: time.Second, MaxDelay: time.Minute, MaxAttempts: 3}}
+
+			redirected, err := cs.RedirectMessage("21ee95f6-3776-4b1e-aabc-742eb5dc9170", `{"messages":[]}`)
+			assert.NoError(t, err)
+			assert.Equal(t, tc.Redirected, redirected)
+			assert.Len(t, repo.queued, tc.Queued)
+			assert.Len(t, repo.deadLetters, tc.DeadLetter)
+			for _, forward := range repo.deadLetters {
+				assert.Equal(t, `{"messages":[]}`, forward.Payload)
+				assert.Equal(t, "courier responded with status 400", forward.LastError)
+			}
+		})
+	}
+}
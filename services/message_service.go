@@ -0,0 +1,74 @@
+package services
+
+import (
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+type MessageService interface {
+	CreateMessage(*models.Message) (*models.Message, error)
+	FindMessageByMessageID(string) (*models.Message, error)
+	MarkAsProcessed(string) (bool, error)
+	UnmarkAsProcessed(string) error
+}
+
+type DefaultMessageService struct {
+	repo          repositories.MessageRepository
+	processedRepo repositories.ProcessedMessageRepository
+}
+
+func (s DefaultMessageService) CreateMessage(req *models.Message) (*models.Message, error) {
+	m := &models.Message{
+		MessageID: req.MessageID,
+		URN:       req.URN,
+		Channel:   req.Channel,
+		CreatedAt: time.Now(),
+	}
+	if err := s.repo.Insert(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (s DefaultMessageService) FindMessageByMessageID(messageID string) (*models.Message, error) {
+	m, err := s.repo.FindByMessageID(messageID)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MarkAsProcessed registers an incoming message id, returning false if it was
+// already registered by a previous delivery of the same message. Messages
+// without id are never registered.
+func (s DefaultMessageService) MarkAsProcessed(messageID string) (bool, error) {
+	if messageID == "" {
+		return true, nil
+	}
+	err := s.processedRepo.Insert(&models.ProcessedMessage{
+		ID:        messageID,
+		CreatedAt: time.Now(),
+	})
+	if err == repositories.ErrDuplicate {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// UnmarkAsProcessed removes the registration of an incoming message id, so a
+// new delivery of a message that failed to be handled is not discarded.
+func (s DefaultMessageService) UnmarkAsProcessed(messageID string) error {
+	if messageID == "" {
+		return nil
+	}
+	return s.processedRepo.Delete(messageID)
+}
+
+func NewMessageService(repo repositories.MessageRepository, processedRepo repositories.ProcessedMessageRepository) DefaultMessageService {
+	return DefaultMessageService{repo, processedRepo}
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+// fakeProcessedMessageRepository keeps the processed message ids in memory.
+type fakeProcessedMessageRepository struct {
+	repositories.ProcessedMessageRepository
+	ids map[string]bool
+}
+
+func (f *fakeProcessedMessageRepository) Insert(msg *models.ProcessedMessage) error {
+	if f.ids[msg.ID] {
+		return repositories.ErrDuplicate
+	}
+	f.ids[msg.ID] = true
+	return nil
+}
+
+func (f *fakeProcessedMessageRepository) Delete(id string) error {
+	delete(f.ids, id)
+	return nil
+}
+
+func TestMarkAsProcessed(t *testing.T) {
+	processedRepo := &fakeProcessedMessageRepository{ids: map[string]bool{}}
+	s := NewMessageService(nil, processedRepo)
+
+	isNew, err := s.MarkAsProcessed("ABGGFlA5FpafAgo6EhRBxk1VOq-l")
+	assert.NoError(t, err)
+	assert.True(t, isNew)
+	isNew, err = s.MarkAsProcessed("ABGGFlA5FpafAgo6EhRBxk1VOq-l")
+	assert.NoError(t, err)
+	assert.False(t, isNew)
+
+	// messages without id are never registered, nor taken as duplicates
+	for i := 0; i < 2; i++ {
+		isNew, err = s.MarkAsProcessed("")
+		assert.NoError(t, err)
+		assert.True(t, isNew)
+	}
+	assert.NoError(t, s.UnmarkAsProcessed(""))
+	assert.Equal(t, map[string]bool{"ABGGFlA5FpafAgo6EhRBxk1VOq-l": true}, processedRepo.ids)
+}
@@ -0,0 +1,262 @@
+package services
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/weni/whatsapp-router/config"
+)
+
+// RateLimitError is returned when a message could not be sent because the
+// send queue of the whatsapp number is full or the message waited too long in
+// it, RetryAfter being an estimate of when sending may succeed.
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("whatsapp send rate limit exceeded, retry after %s", e.RetryAfter)
+}
+
+// RateLimiter is a token bucket shared by all the channels sending messages
+// through a whatsapp number. When the bucket is empty sends are queued per
+// channel and released in turns, so a channel sending a burst gets only its
+// share of the rate and of the queue while other channels are waiting.
+type RateLimiter struct {
+	rate      float64
+	burst     float64
+	queueSize int
+	maxWait   time.Duration
+	now       func() time.Time
+
+	mu      sync.Mutex
+	tokens  float64
+	last    time.Time
+	queues  map[string][]*rateWaiter
+	order   []string
+	next    int
+	waiting int
+	timer   *time.Timer
+}
+
+type rateWaiter struct {
+	ready   chan struct{}
+	granted bool
+}
+
+// NewRateLimiter returns a limiter allowing rate sends per second, bursts of
+// up to burst sends, and queueing up to queueSize sends for at most maxWait.
+func NewRateLimiter(rate float64, burst int, queueSize int, maxWait time.Duration) *RateLimiter {
+	if burst < 1 {
+		burst = 1
+	}
+	return &RateLimiter{
+		rate:      rate,
+		burst:     float64(burst),
+		queueSize: queueSize,
+		maxWait:   maxWait,
+		now:       time.Now,
+		tokens:    float64(burst),
+		last:      time.Now(),
+		queues:    map[string][]*rateWaiter{},
+	}
+}
+
+// NewConfigRateLimiter returns a limiter with the rate configured by the
+// environment, or nil if rate limiting is disabled.
+func NewConfigRateLimiter() *RateLimiter {
+	rconfig := config.GetConfig().Whatsapp.RateLimit
+	if rconfig.Rate <= 0 {
+		return nil
+	}
+	return NewRateLimiter(rconfig.Rate, rconfig.Burst, rconfig.QueueSize, rconfig.MaxWait)
+}
+
+// Wait blocks until the channel may send a message, returning a
+// *RateLimitError if the queue is full or the wait takes longer than allowed.
+func (l *RateLimiter) Wait(channel string) error {
+	l.mu.Lock()
+	l.refill()
+	if l.waiting == 0 && l.tokens >= 1 {
+		l.tokens--
+		l.mu.Unlock()
+		return nil
+	}
+	if l.waiting >= l.queueSize || len(l.queues[channel]) >= l.channelShare(channel) {
+		retryAfter := l.estimate(l.waiting + 1)
+		l.mu.Unlock()
+		return &RateLimitError{RetryAfter: retryAfter}
+	}
+	w := &rateWaiter{ready: make(chan struct{})}
+	if len(l.queues[channel]) == 0 {
+		l.order = append(l.order, channel)
+	}
+	l.queues[channel] = append(l.queues[channel], w)
+	l.waiting++
+	l.schedule()
+	l.mu.Unlock()
+
+	timeout := time.NewTimer(l.maxWait)
+	defer timeout.Stop()
+	select {
+	case <-w.ready:
+		return nil
+	case <-timeout.C:
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if w.granted {
+		return nil
+	}
+	l.remove(channel, w)
+	return &RateLimitError{RetryAfter: l.estimate(l.waiting + 1)}
+}
+
+// channelShare is the number of sends a channel may have queued, the queue
+// being split between the channels waiting.
+func (l *RateLimiter) channelShare(channel string) int {
+	channels := len(l.order)
+	if len(l.queues[channel]) == 0 {
+		channels++
+	}
+	share := l.queueSize / channels
+	if share < 1 {
+		share = 1
+	}
+	return share
+}
+
+// estimate returns how long it takes for n sends to get a token.
+func (l *RateLimiter) estimate(n int) time.Duration {
+	missing := float64(n) - l.tokens
+	if missing <= 0 {
+		return 0
+	}
+	return time.Duration(math.Ceil(missing / l.rate * float64(time.Second)))
+}
+
+func (l *RateLimiter) refill() {
+	now := l.now()
+	l.tokens = math.Min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+}
+
+// schedule arms the timer releasing the queued sends when the next token is
+// available.
+func (l *RateLimiter) schedule() {
+	if l.timer != nil || l.waiting == 0 {
+		return
+	}
+	l.timer = time.AfterFunc(l.estimate(1), l.release)
+}
+
+// release hands the available tokens to the queued sends, one channel at a
+// time.
+func (l *RateLimiter) release() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.timer = nil
+	l.refill()
+	for l.waiting > 0 && l.tokens >= 1 {
+		if l.next >= len(l.order) {
+			l.next = 0
+		}
+		channel := l.order[l.next]
+		w := l.queues[channel][0]
+		l.queues[channel] = l.queues[channel][1:]
+		l.waiting--
+		l.tokens--
+		w.granted = true
+		close(w.ready)
+		if len(l.queues[channel]) == 0 {
+			l.dropChannel(l.next)
+		} else {
+			l.next++
+		}
+	}
+	l.schedule()
+}
+
+func (l *RateLimiter) remove(channel string, w *rateWaiter) {
+	queue := l.queues[channel]
+	for i, queued := range queue {
+		if queued == w {
+			l.queues[channel] = append(queue[:i], queue[i+1:]...)
+			l.waiting--
+			break
+		}
+	}
+	if len(l.queues[channel]) > 0 {
+		return
+	}
+	for i, c := range l.order {
+		if c == channel {
+			l.dropChannel(i)
+			return
+		}
+	}
+}
+
+// dropChannel removes the channel at position i of the turns, keeping the turn
+// of the next channel.
+func (l *RateLimiter) dropChannel(i int) {
+	delete(l.queues, l.order[i])
+	l.order = append(l.order[:i], l.order[i+1:]...)
+	if i < l.next {
+		l.next--
+	}
+}
+
+// RateLimitedWhatsappService limits the messages sent by a whatsapp service,
+// sharing its rate between the channels sending through it.
+type RateLimitedWhatsappService struct {
+	WhatsappService
+	limiter *RateLimiter
+	channel string
+}
+
+func NewRateLimitedWhatsappService(ws WhatsappService, limiter *RateLimiter) RateLimitedWhatsappService {
+	return RateLimitedWhatsappService{WhatsappService: ws, limiter: limiter}
+}
+
+// SendMessage waits for the turn of the channel before sending the message,
+// returning a *RateLimitError without sending it if the wait is too long.
+func (ws RateLimitedWhatsappService) SendMessage(body []byte) (http.Header, io.ReadCloser, error) {
+	if err := ws.limiter.Wait(ws.channel); err != nil {
+		return nil, nil, err
+	}
+	return ws.WhatsappService.SendMessage(body)
+}
+
+// ForChannel returns the service sending the messages of the channel.
+func (ws RateLimitedWhatsappService) ForChannel(channel string) WhatsappService {
+	ws.channel = channel
+	return ws
+}
+
+// ChannelWhatsappService returns the whatsapp service sending the messages of
+// the channel, which only differs from the given one when it is rate limited.
+func ChannelWhatsappService(ws WhatsappService, channel string) WhatsappService {
+	if limited, ok := ws.(interface {
+		ForChannel(string) WhatsappService
+	}); ok {
+		return limited.ForChannel(channel)
+	}
+	return ws
+}
+
+// rateLimited limits the whatsapp service with the rate configured by the
+// environment, each service getting its own limiter as each account has its
+// own number.
+func rateLimited(ws WhatsappService) WhatsappService {
+	limiter := NewConfigRateLimiter()
+	if limiter == nil {
+		return ws
+	}
+	return NewRateLimitedWhatsappService(ws, limiter)
+}
@@ -0,0 +1,70 @@
+package services
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// waitQueued waits until the limiter has n sends queued.
+func waitQueued(t *testing.T, l *RateLimiter, n int) {
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		l.mu.Lock()
+		waiting := l.waiting
+		l.mu.Unlock()
+		if waiting == n {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("limiter has not queued %d sends", n)
+}
+
+func TestRateLimiterFairShare(t *testing.T) {
+	l := NewRateLimiter(20, 1, 4, time.Second)
+	assert.NoError(t, l.Wait("a"))
+
+	var mu sync.Mutex
+	var granted []string
+	var wg sync.WaitGroup
+	wait := func(channel string) {
+		defer wg.Done()
+		assert.NoError(t, l.Wait(channel))
+		mu.Lock()
+		granted = append(granted, channel)
+		mu.Unlock()
+	}
+	for i, channel := range []string{"a", "a", "b"} {
+		wg.Add(1)
+		go wait(channel)
+		waitQueued(t, l, i+1)
+	}
+
+	// a already has its share of the queue while b is waiting
+	var rateLimitErr *RateLimitError
+	assert.True(t, errors.As(l.Wait("a"), &rateLimitErr))
+	assert.Greater(t, int64(rateLimitErr.RetryAfter), int64(0))
+
+	wg.Add(1)
+	go wait("b")
+	waitQueued(t, l, 4)
+	// the queue is full
+	assert.True(t, errors.As(l.Wait("c"), &rateLimitErr))
+
+	wg.Wait()
+	assert.Equal(t, []string{"a", "b", "a", "b"}, granted)
+}
+
+func TestRateLimiterMaxWait(t *testing.T) {
+	l := NewRateLimiter(1, 1, 10, 20*time.Millisecond)
+	assert.NoError(t, l.Wait("a"))
+
+	var rateLimitErr *RateLimitError
+	assert.True(t, errors.As(l.Wait("a"), &rateLimitErr))
+	assert.Equal(t, 0, l.waiting)
+	assert.Empty(t, l.order)
+}
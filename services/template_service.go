@@ -0,0 +1,211 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var templateParameterRegexp = regexp.MustCompile(`{{\s*(\d+)\s*}}`)
+
+var ErrInvalidTemplate = errors.New("template name and language could not be empty and parameter count could not be negative")
+
+var ErrInvalidTemplateMessage = errors.New("invalid template message")
+
+// TemplateService manages the registry of the message templates of each
+// whatsapp account and builds the template messages sent to contacts.
+type TemplateService interface {
+	SaveTemplate(*models.Template) (*models.Template, error)
+	ListTemplates(string) ([]*models.Template, error)
+	DeleteTemplate(account string, name string, language string) error
+	SyncTemplates(string, WhatsappService) (int, error)
+	TemplateMessage(account string, msg *TemplateMessage) ([]byte, error)
+}
+
+// TemplateMessage is a message of a registered template with the parameters
+// of its body.
+type TemplateMessage struct {
+	To         string   `json:"to"`
+	Name       string   `json:"name"`
+	Language   string   `json:"language"`
+	Parameters []string `json:"parameters"`
+}
+
+type DefaultTemplateService struct {
+	repo repositories.TemplateRepository
+}
+
+// SaveTemplate registers the template, replacing the one with the same name
+// and language, and returns ErrInvalidTemplate if it is not valid.
+func (s DefaultTemplateService) SaveTemplate(template *models.Template) (*models.Template, error) {
+	if template.Name == "" || template.Language == "" || template.ParameterCount < 0 {
+		return nil, ErrInvalidTemplate
+	}
+	template.ID = primitive.NilObjectID
+	template.UpdatedAt = time.Now().UTC()
+	if err := s.repo.Upsert(template); err != nil {
+		return nil, err
+	}
+	return template, nil
+}
+
+func (s DefaultTemplateService) ListTemplates(account string) ([]*models.Template, error) {
+	return s.repo.FindAll(account)
+}
+
+func (s DefaultTemplateService) DeleteTemplate(account string, name string, language string) error {
+	return s.repo.Delete(account, name, language)
+}
+
+// SyncTemplates saves the templates of the whatsapp api of the account,
+// returning how many were saved, or ErrTemplatesNotSupported if the api does
+// not manage templates.
+func (s DefaultTemplateService) SyncTemplates(account string, ws WhatsappService) (int, error) {
+	res, err := ws.GetTemplates()
+	if err != nil {
+		return 0, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode == http.StatusNotFound {
+		return 0, ErrTemplatesNotSupported
+	}
+	if res.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("whatsapp api responded %s to templates request", res.Status)
+	}
+
+	var templates whatsappTemplates
+	if err := json.NewDecoder(res.Body).Decode(&templates); err != nil {
+		return 0, err
+	}
+	synced := 0
+	for _, t := range templates.Data {
+		namespace := t.Namespace
+		if namespace == "" {
+			namespace = templates.Namespace
+		}
+		template := &models.Template{
+			Account:        account,
+			Name:           t.Name,
+			Language:       t.Language,
+			Namespace:      namespace,
+			Category:       t.Category,
+			Status:         t.Status,
+			ParameterCount: t.parameterCount(),
+		}
+		if _, err := s.SaveTemplate(template); err != nil {
+			return synced, fmt.Errorf("template %s %s: %w", t.Name, t.Language, err)
+		}
+		synced++
+	}
+	return synced, nil
+}
+
+// TemplateMessage builds the message of the template of the account in the
+// on-premises api format, returning ErrInvalidTemplateMessage if the template
+// can not be sent or the number of parameters does not match.
+func (s DefaultTemplateService) TemplateMessage(account string, msg *TemplateMessage) ([]byte, error) {
+	if msg.To == "" {
+		return nil, fmt.Errorf("%w: recipient could not be empty", ErrInvalidTemplateMessage)
+	}
+	template, err := s.repo.FindOne(account, msg.Name, msg.Language)
+	if err != nil {
+		return nil, fmt.Errorf("template %s %s %w", msg.Name, msg.Language, err)
+	}
+	if !template.IsSendable() {
+		return nil, fmt.Errorf("%w: template %s %s is %s", ErrInvalidTemplateMessage, msg.Name, msg.Language, template.Status)
+	}
+	if len(msg.Parameters) != template.ParameterCount {
+		return nil, fmt.Errorf(
+			"%w: template %s %s expects %d parameters, got %d",
+			ErrInvalidTemplateMessage, msg.Name, msg.Language, template.ParameterCount, len(msg.Parameters),
+		)
+	}
+
+	body := templateBody{
+		Namespace: template.Namespace,
+		Name:      template.Name,
+		Language:  templateLanguage{Policy: "deterministic", Code: template.Language},
+	}
+	if len(msg.Parameters) > 0 {
+		component := templateComponent{Type: "body"}
+		for _, p := range msg.Parameters {
+			component.Parameters = append(component.Parameters, templateParameter{Type: "text", Text: p})
+		}
+		body.Components = []templateComponent{component}
+	}
+	return json.Marshal(map[string]interface{}{
+		"to":       msg.To,
+		"type":     "template",
+		"template": body,
+	})
+}
+
+// whatsappTemplates is the response of the templates request of the whatsapp
+// api.
+type whatsappTemplates struct {
+	Namespace string             `json:"message_template_namespace"`
+	Data      []whatsappTemplate `json:"data"`
+}
+
+type whatsappTemplate struct {
+	Name       string `json:"name"`
+	Language   string `json:"language"`
+	Namespace  string `json:"namespace"`
+	Category   string `json:"category"`
+	Status     string `json:"status"`
+	Components []struct {
+		Type string `json:"type"`
+		Text string `json:"text"`
+	} `json:"components"`
+}
+
+// parameterCount returns the number of parameters of the body of the
+// template, the highest {{n}} placeholder of its text.
+func (t whatsappTemplate) parameterCount() int {
+	count := 0
+	for _, c := range t.Components {
+		if c.Type != "BODY" && c.Type != "body" {
+			continue
+		}
+		for _, match := range templateParameterRegexp.FindAllStringSubmatch(c.Text, -1) {
+			if n, _ := strconv.Atoi(match[1]); n > count {
+				count = n
+			}
+		}
+	}
+	return count
+}
+
+type templateBody struct {
+	Namespace  string              `json:"namespace,omitempty"`
+	Name       string              `json:"name"`
+	Language   templateLanguage    `json:"language"`
+	Components []templateComponent `json:"components,omitempty"`
+}
+
+type templateLanguage struct {
+	Policy string `json:"policy"`
+	Code   string `json:"code"`
+}
+
+type templateComponent struct {
+	Type       string              `json:"type"`
+	Parameters []templateParameter `json:"parameters"`
+}
+
+type templateParameter struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+func NewTemplateService(repo repositories.TemplateRepository) DefaultTemplateService {
+	return DefaultTemplateService{repo}
+}
@@ -0,0 +1,132 @@
+package services
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+// memTemplateRepository keeps the templates in memory, by account, name and
+// language.
+type memTemplateRepository map[string]*models.Template
+
+func (m memTemplateRepository) Upsert(t *models.Template) error {
+	m[t.Account+"/"+t.Name+"/"+t.Language] = t
+	return nil
+}
+
+func (m memTemplateRepository) FindOne(account string, name string, language string) (*models.Template, error) {
+	t, ok := m[account+"/"+name+"/"+language]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return t, nil
+}
+
+func (m memTemplateRepository) FindAll(account string) ([]*models.Template, error) {
+	templates := []*models.Template{}
+	for _, t := range m {
+		if t.Account == account {
+			templates = append(templates, t)
+		}
+	}
+	return templates, nil
+}
+
+func (m memTemplateRepository) Delete(account string, name string, language string) error {
+	delete(m, account+"/"+name+"/"+language)
+	return nil
+}
+
+func (m memTemplateRepository) CreateIndexes() error { return nil }
+
+func TestSyncTemplates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	body := `{
+		"message_template_namespace": "c8ae5f90_307a_ca4c_b8f6_d1e2a2573574",
+		"data": [
+			{"name": "welcome", "language": "pt_BR", "status": "APPROVED", "category": "MARKETING", "components": [
+				{"type": "HEADER", "text": "Olá {{1}}"},
+				{"type": "BODY", "text": "Olá {{1}}, seu código é {{2}}. Use {{1}} para entrar."}
+			]},
+			{"name": "farewell", "language": "en", "status": "REJECTED", "components": [{"type": "BODY", "text": "Bye"}]}
+		]
+	}`
+	mockWhatsappService := mocks.NewMockWhatsappService(ctrl)
+	mockWhatsappService.EXPECT().GetTemplates().Return(&http.Response{
+		StatusCode: http.StatusOK,
+		Body:       io.NopCloser(strings.NewReader(body)),
+	}, nil)
+
+	repo := memTemplateRepository{}
+	s := NewTemplateService(repo)
+	synced, err := s.SyncTemplates("acme", mockWhatsappService)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, synced)
+
+	welcome, err := repo.FindOne("acme", "welcome", "pt_BR")
+	assert.NoError(t, err)
+	assert.Equal(t, 2, welcome.ParameterCount)
+	assert.Equal(t, "c8ae5f90_307a_ca4c_b8f6_d1e2a2573574", welcome.Namespace)
+	assert.True(t, welcome.IsSendable())
+
+	farewell, err := repo.FindOne("acme", "farewell", "en")
+	assert.NoError(t, err)
+	assert.Equal(t, 0, farewell.ParameterCount)
+	assert.False(t, farewell.IsSendable())
+
+	mockWhatsappService.EXPECT().GetTemplates().Return(&http.Response{
+		StatusCode: http.StatusNotFound,
+		Body:       io.NopCloser(strings.NewReader(`{}`)),
+	}, nil)
+	_, err = s.SyncTemplates("acme", mockWhatsappService)
+	assert.ErrorIs(t, err, ErrTemplatesNotSupported)
+}
+
+func TestTemplateMessage(t *testing.T) {
+	repo := memTemplateRepository{}
+	s := NewTemplateService(repo)
+	_, err := s.SaveTemplate(&models.Template{Name: "welcome", Language: "pt_BR", Namespace: "weni", ParameterCount: 2})
+	assert.NoError(t, err)
+	_, err = s.SaveTemplate(&models.Template{Name: "paused", Language: "pt_BR", Status: "PAUSED"})
+	assert.NoError(t, err)
+	_, err = s.SaveTemplate(&models.Template{Name: "welcome"})
+	assert.ErrorIs(t, err, ErrInvalidTemplate)
+
+	msg, err := s.TemplateMessage("", &TemplateMessage{
+		To:         "5582988887777",
+		Name:       "welcome",
+		Language:   "pt_BR",
+		Parameters: []string{"Maria", "1234"},
+	})
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"to": "5582988887777",
+		"type": "template",
+		"template": {
+			"namespace": "weni",
+			"name": "welcome",
+			"language": {"policy": "deterministic", "code": "pt_BR"},
+			"components": [{"type": "body", "parameters": [
+				{"type": "text", "text": "Maria"},
+				{"type": "text", "text": "1234"}
+			]}]
+		}
+	}`, string(msg))
+
+	_, err = s.TemplateMessage("", &TemplateMessage{To: "5582988887777", Name: "welcome", Language: "pt_BR"})
+	assert.ErrorIs(t, err, ErrInvalidTemplateMessage)
+	_, err = s.TemplateMessage("", &TemplateMessage{To: "5582988887777", Name: "paused", Language: "pt_BR"})
+	assert.ErrorIs(t, err, ErrInvalidTemplateMessage)
+	_, err = s.TemplateMessage("", &TemplateMessage{To: "5582988887777", Name: "welcome", Language: "en"})
+	assert.ErrorIs(t, err, repositories.ErrNotFound)
+}
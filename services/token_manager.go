@@ -0,0 +1,149 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+)
+
+// expiresAfterLayout is the layout of the expires_after of whatsapp logins.
+const expiresAfterLayout = "2006-01-02 15:04:05-07:00"
+
+// defaultTokenLifetime is used when the expiration of a token is unknown,
+// whatsapp tokens are valid for 7 days.
+const defaultTokenLifetime = 7 * 24 * time.Hour
+
+// TokenManager keeps the whatsapp auth token of the token store valid,
+// logging in again ahead of its expiration or when whatsapp rejects it.
+type TokenManager interface {
+	Refresh(string) (string, error)
+	RefreshIfExpiring()
+}
+
+type DefaultTokenManager struct {
+	store         TokenStore
+	configService ConfigService
+	login         func() (*http.Response, error)
+	refreshAhead  time.Duration
+
+	// mu serializes logins, so concurrent refreshes of the same token
+	// trigger a single login.
+	mu        sync.Mutex
+	expiresAt time.Time
+}
+
+// Start loads the persisted token, logging in when there is none or when it
+// is about to expire.
+func (m *DefaultTokenManager) Start() error {
+	conf, err := m.configService.GetConfig()
+	if err != nil {
+		return err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if conf == nil || conf.Token == "" {
+		return m.refresh()
+	}
+	m.store.Set(conf.Token)
+	m.expiresAt = conf.ExpiresAt
+	if m.expiring() {
+		return m.refresh()
+	}
+	return nil
+}
+
+// Refresh logs in again unless the stale token has already been replaced by
+// a concurrent refresh, returning the current token.
+func (m *DefaultTokenManager) Refresh(staleToken string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if token := m.store.Get(); token != staleToken {
+		return token, nil
+	}
+	if err := m.refresh(); err != nil {
+		return "", err
+	}
+	return m.store.Get(), nil
+}
+
+// RefreshIfExpiring logs in again when the token expires in less than the
+// configured refresh ahead duration.
+func (m *DefaultTokenManager) RefreshIfExpiring() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if !m.expiring() {
+		return
+	}
+	if err := m.refresh(); err != nil {
+		logger.Error(fmt.Sprintf("Couldn't update token: %s", err))
+	}
+}
+
+func (m *DefaultTokenManager) expiring() bool {
+	return time.Until(m.expiresAt) < m.refreshAhead
+}
+
+// refresh logs in and stores the new token, it must be called holding mu.
+func (m *DefaultTokenManager) refresh() error {
+	res, err := m.login()
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	bdBytes, err := io.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("whatsapp login responded with %s: %s", res.Status, string(bdBytes))
+	}
+	var login LoginWhatsapp
+	if err := json.Unmarshal(bdBytes, &login); err != nil {
+		return err
+	}
+	if len(login.Users) == 0 || login.Users[0].Token == "" {
+		return errors.New("whatsapp login responded without token")
+	}
+
+	token := login.Users[0].Token
+	expiresAt := login.ExpiresAt()
+	if _, err := m.configService.CreateOrUpdate(&models.Config{Token: token, ExpiresAt: expiresAt}); err != nil {
+		logger.Error(err.Error())
+	}
+	m.store.Set(token)
+	m.expiresAt = expiresAt
+	logger.Info(fmt.Sprintf("Whatsapp token updated, expires at %s", expiresAt))
+	return nil
+}
+
+// ExpiresAt returns when the token of the login expires, or the default token
+// lifetime from now if it is unknown.
+func (l LoginWhatsapp) ExpiresAt() time.Time {
+	if len(l.Users) > 0 {
+		expiresAt, err := time.Parse(expiresAfterLayout, l.Users[0].ExpiresAfter)
+		if err == nil {
+			return expiresAt
+		}
+		logger.Debug(fmt.Sprintf("unknown token expiration %q: %s", l.Users[0].ExpiresAfter, err))
+	}
+	return time.Now().Add(defaultTokenLifetime)
+}
+
+// NewTokenManager returns the token manager of the account, persisting its
+// token with the config service.
+func NewTokenManager(account *models.WhatsappAccount, store TokenStore, configService ConfigService) *DefaultTokenManager {
+	return &DefaultTokenManager{
+		store:         store,
+		configService: configService,
+		login:         func() (*http.Response, error) { return whatsappLogin(account) },
+		refreshAhead:  config.GetConfig().Whatsapp.TokenRefreshAhead,
+	}
+}
@@ -0,0 +1,106 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	mocks "github.com/weni/whatsapp-router/mocks/services"
+	"github.com/weni/whatsapp-router/models"
+)
+
+func newTestTokenManager(t *testing.T, store TokenStore, logins *int32) *DefaultTokenManager {
+	ctrl := gomock.NewController(t)
+	mockConfigService := mocks.NewMockConfigService(ctrl)
+	mockConfigService.EXPECT().CreateOrUpdate(gomock.Any()).Return(nil, nil).AnyTimes()
+
+	return &DefaultTokenManager{
+		store:         store,
+		configService: mockConfigService,
+		refreshAhead:  time.Hour,
+		login: func() (*http.Response, error) {
+			atomic.AddInt32(logins, 1)
+			// slow login, so concurrent refreshes wait for it
+			time.Sleep(10 * time.Millisecond)
+			body := `{"users":[{"token":"new-token","expires_after":"2099-12-29 14:12:02+00:00"}]}`
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       io.NopCloser(bytes.NewReader([]byte(body))),
+			}, nil
+		},
+	}
+}
+
+func TestTokenManagerSingleRefresh(t *testing.T) {
+	var logins int32
+	store := NewTokenStore()
+	store.Set("old-token")
+	tm := newTestTokenManager(t, store, &logins)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := tm.Refresh("old-token")
+			assert.NoError(t, err)
+			assert.Equal(t, "new-token", token)
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+	assert.Equal(t, "new-token", store.Get())
+	assert.Equal(t, 2099, tm.expiresAt.Year())
+}
+
+func TestTokenManagerRefreshIfExpiring(t *testing.T) {
+	var logins int32
+	store := NewTokenStore()
+	store.Set("old-token")
+	tm := newTestTokenManager(t, store, &logins)
+
+	tm.expiresAt = time.Now().Add(2 * time.Hour)
+	tm.RefreshIfExpiring()
+	assert.Equal(t, int32(0), atomic.LoadInt32(&logins))
+
+	tm.expiresAt = time.Now().Add(30 * time.Minute)
+	tm.RefreshIfExpiring()
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+	assert.Equal(t, "new-token", store.Get())
+}
+
+func TestWhatsappServiceRetryOnUnauthorized(t *testing.T) {
+	var logins int32
+	store := NewTokenStore()
+	store.Set("old-token")
+	tm := newTestTokenManager(t, store, &logins)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		if r.Header.Get("Authorization") != "Bearer new-token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"messages":[{"id":"gBEGVYKZRIIyAgmiTgezkroUL2Q"}]}`))
+	}))
+	defer server.Close()
+
+	ws := NewWhatsappService(&models.WhatsappAccount{BaseURL: server.URL}, store, tm)
+	_, body, err := ws.SendMessage([]byte(`{"to":"5582988887777","type":"text","text":{"body":"hi"}}`))
+	assert.NoError(t, err)
+	b, _ := io.ReadAll(body)
+	body.Close()
+	assert.Equal(t, `{"messages":[{"id":"gBEGVYKZRIIyAgmiTgezkroUL2Q"}]}`, string(b))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&requests))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&logins))
+}
@@ -0,0 +1,30 @@
+package services
+
+import "sync"
+
+// TokenStore holds the whatsapp auth token shared by every request.
+type TokenStore interface {
+	Get() string
+	Set(string)
+}
+
+type DefaultTokenStore struct {
+	mu    sync.RWMutex
+	token string
+}
+
+func (s *DefaultTokenStore) Get() string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.token
+}
+
+func (s *DefaultTokenStore) Set(token string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.token = token
+}
+
+func NewTokenStore() *DefaultTokenStore {
+	return &DefaultTokenStore{}
+}
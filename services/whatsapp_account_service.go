@@ -0,0 +1,206 @@
+package services
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/weni/whatsapp-router/logger"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+	"github.com/weni/whatsapp-router/utils"
+)
+
+var accountNameRegexp = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// loginRetryDelay is how long a failed login of an account is remembered
+// before the account is logged in again.
+const loginRetryDelay = 30 * time.Second
+
+var ErrInvalidAccount = errors.New("account name must have only lower case letters, numbers and dashes, base url could not be empty, webhook allowed ips must be ips or networks, and on-premises accounts need an username while cloud accounts need a phone number id and an access token")
+
+// WhatsappAccountService manages the whatsapp accounts stored in the database,
+// each one with its own whatsapp service and auth token.
+type WhatsappAccountService interface {
+	CreateAccount(*models.WhatsappAccount) (*models.WhatsappAccount, error)
+	ListAccounts() ([]*models.WhatsappAccount, error)
+	DeleteAccount(string) error
+	WhatsappService(string) (WhatsappService, error)
+	RefreshTokens()
+}
+
+type DefaultWhatsappAccountService struct {
+	repo repositories.WhatsappAccountRepository
+
+	mu      sync.Mutex
+	clients map[string]*whatsappClient
+}
+
+// whatsappClient is the whatsapp service of an account, ready once the
+// account is logged in. A failed login is kept until loginRetryDelay passes.
+type whatsappClient struct {
+	ready    chan struct{}
+	service  WhatsappService
+	tokens   TokenManager
+	err      error
+	failedAt time.Time
+}
+
+// CreateAccount returns ErrInvalidAccount if the account is not valid and
+// repositories.ErrDuplicate if its name is already in use.
+func (s *DefaultWhatsappAccountService) CreateAccount(account *models.WhatsappAccount) (*models.WhatsappAccount, error) {
+	if !validAccount(account) {
+		return nil, ErrInvalidAccount
+	}
+	account.Token = ""
+	if err := s.repo.Insert(account); err != nil {
+		return nil, err
+	}
+	return account, nil
+}
+
+func validAccount(account *models.WhatsappAccount) bool {
+	if !accountNameRegexp.MatchString(account.Name) || account.BaseURL == "" {
+		return false
+	}
+	if _, err := utils.ParseIPNets(account.WebhookAllowedIPs); err != nil {
+		return false
+	}
+	switch account.Provider {
+	case "", models.ProviderOnPremise:
+		return account.Username != ""
+	case models.ProviderCloud:
+		return account.PhoneNumberID != "" && account.AccessToken != ""
+	}
+	return false
+}
+
+func (s *DefaultWhatsappAccountService) ListAccounts() ([]*models.WhatsappAccount, error) {
+	return s.repo.FindAll()
+}
+
+func (s *DefaultWhatsappAccountService) DeleteAccount(name string) error {
+	if err := s.repo.Delete(name); err != nil {
+		return err
+	}
+	s.mu.Lock()
+	delete(s.clients, name)
+	s.mu.Unlock()
+	return nil
+}
+
+// WhatsappService returns the whatsapp service of the account with the given
+// name, logging in the on-premises accounts the first time they are used.
+// Accounts are logged in without holding the lock of the other accounts, and
+// concurrent requests of an account wait for the same login.
+func (s *DefaultWhatsappAccountService) WhatsappService(name string) (WhatsappService, error) {
+	for {
+		s.mu.Lock()
+		client, ok := s.clients[name]
+		if !ok {
+			client = &whatsappClient{ready: make(chan struct{})}
+			s.clients[name] = client
+			s.mu.Unlock()
+			s.login(name, client)
+			return client.service, client.err
+		}
+		s.mu.Unlock()
+
+		<-client.ready
+		if client.err == nil || time.Since(client.failedAt) < loginRetryDelay {
+			return client.service, client.err
+		}
+		s.remove(name, client)
+	}
+}
+
+// login creates the whatsapp service of the account, logging in the
+// on-premises accounts. Only failed logins are kept, other errors are
+// returned to the requests waiting for the client and then forgotten.
+func (s *DefaultWhatsappAccountService) login(name string, client *whatsappClient) {
+	defer close(client.ready)
+	account, err := s.repo.FindByName(name)
+	if err != nil {
+		client.err = err
+		s.remove(name, client)
+		return
+	}
+	if account.IsCloud() {
+		client.service = NewAccountWhatsappService(account, nil, nil)
+		return
+	}
+	store := NewTokenStore()
+	tokens := NewTokenManager(account, store, accountTokenConfig{s.repo, name})
+	if err := tokens.Start(); err != nil {
+		logger.Error(err.Error())
+		client.err = err
+		client.failedAt = time.Now()
+		return
+	}
+	client.service = NewAccountWhatsappService(account, store, tokens)
+	client.tokens = tokens
+}
+
+// remove removes the client of the account, unless it was already replaced.
+func (s *DefaultWhatsappAccountService) remove(name string, client *whatsappClient) {
+	s.mu.Lock()
+	if s.clients[name] == client {
+		delete(s.clients, name)
+	}
+	s.mu.Unlock()
+}
+
+// RefreshTokens refreshes the auth tokens about to expire of the on-premises
+// accounts in use.
+func (s *DefaultWhatsappAccountService) RefreshTokens() {
+	s.mu.Lock()
+	clients := make([]*whatsappClient, 0, len(s.clients))
+	for _, client := range s.clients {
+		select {
+		case <-client.ready:
+			if client.tokens != nil {
+				clients = append(clients, client)
+			}
+		default:
+		}
+	}
+	s.mu.Unlock()
+
+	for _, client := range clients {
+		client.tokens.RefreshIfExpiring()
+	}
+}
+
+// accountTokenConfig persists the auth token of a whatsapp account, as the
+// config service does for the default account.
+type accountTokenConfig struct {
+	repo repositories.WhatsappAccountRepository
+	name string
+}
+
+func (c accountTokenConfig) GetConfig() (*models.Config, error) {
+	account, err := c.repo.FindByName(c.name)
+	if err != nil {
+		return nil, err
+	}
+	if account.Token == "" {
+		return nil, nil
+	}
+	return &models.Config{Token: account.Token, ExpiresAt: account.ExpiresAt}, nil
+}
+
+func (c accountTokenConfig) CreateOrUpdate(conf *models.Config) (*models.Config, error) {
+	if err := c.repo.UpdateToken(c.name, conf.Token, conf.ExpiresAt); err != nil {
+		logger.Error(err.Error())
+		return nil, err
+	}
+	return conf, nil
+}
+
+func NewWhatsappAccountService(repo repositories.WhatsappAccountRepository) *DefaultWhatsappAccountService {
+	return &DefaultWhatsappAccountService{
+		repo:    repo,
+		clients: map[string]*whatsappClient{},
+	}
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/weni/whatsapp-router/models"
+	"github.com/weni/whatsapp-router/repositories"
+)
+
+// fakeAccountRepository keeps the accounts by name in memory.
+type fakeAccountRepository struct {
+	repositories.WhatsappAccountRepository
+	mu       sync.Mutex
+	accounts map[string]models.WhatsappAccount
+}
+
+func (f *fakeAccountRepository) FindByName(name string) (*models.WhatsappAccount, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	account, ok := f.accounts[name]
+	if !ok {
+		return nil, repositories.ErrNotFound
+	}
+	return &account, nil
+}
+
+func (f *fakeAccountRepository) UpdateToken(name string, token string, expiresAt time.Time) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	account := f.accounts[name]
+	account.Token, account.ExpiresAt = token, expiresAt
+	f.accounts[name] = account
+	return nil
+}
+
+func TestWhatsappServiceLogin(t *testing.T) {
+	release := make(chan struct{})
+	var failedLogins int32
+	onPremise := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+		w.Write([]byte(`{"users":[{"token":"slow-token","expires_after":"2099-12-29 14:12:02+00:00"}]}`))
+	}))
+	defer onPremise.Close()
+	down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&failedLogins, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer down.Close()
+
+	repo := &fakeAccountRepository{accounts: map[string]models.WhatsappAccount{
+		"cloud": {Name: "cloud", Provider: models.ProviderCloud, BaseURL: "https://graph.facebook.com", PhoneNumberID: "1", AccessToken: "token"},
+		"slow":  {Name: "slow", BaseURL: onPremise.URL, Username: "admin"},
+		"down":  {Name: "down", BaseURL: down.URL, Username: "admin"},
+	}}
+	s := NewWhatsappAccountService(repo)
+
+	_, err := s.WhatsappService("cloud")
+	assert.NoError(t, err)
+
+	slow := make(chan error)
+	go func() {
+		_, err := s.WhatsappService("slow")
+		slow <- err
+	}()
+
+	// the login of an account does not block the others
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, err := s.WhatsappService("cloud")
+		assert.NoError(t, err)
+		_, err = s.WhatsappService("unknown")
+		assert.True(t, errors.Is(err, repositories.ErrNotFound))
+		for i := 0; i < 3; i++ {
+			_, err = s.WhatsappService("down")
+			assert.Error(t, err)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("accounts blocked by the login of another account")
+	}
+	// the failed login is remembered
+	assert.Equal(t, int32(1), atomic.LoadInt32(&failedLogins))
+
+	close(release)
+	assert.NoError(t, <-slow)
+	_, err = s.WhatsappService("slow")
+	assert.NoError(t, err)
+}
@@ -10,14 +10,16 @@ import (
 	"net/url"
 
 	"github.com/weni/whatsapp-router/config"
+	"github.com/weni/whatsapp-router/models"
 	"github.com/weni/whatsapp-router/utils"
 )
 
 const (
-	messagePath = "/v1/messages"
-	loginPath   = "/v1/users/login"
-	healthPath  = "/v1/health"
-	mediaPath   = "/v1/media/"
+	messagePath   = "/v1/messages"
+	loginPath     = "/v1/users/login"
+	healthPath    = "/v1/health"
+	mediaPath     = "/v1/media/"
+	templatesPath = "/v1/message_templates"
 )
 
 type WhatsappService interface {
@@ -26,38 +28,68 @@ type WhatsappService interface {
 	Health() (*http.Response, error)
 	GetMedia(http.Header, string) (*http.Response, error)
 	PostMedia(http.Header, io.ReadCloser) (*http.Response, error)
+	GetTemplates() (*http.Response, error)
+	Account() *models.WhatsappAccount
 }
 
 type DefaultWhatsappService struct {
+	account *models.WhatsappAccount
+	store   TokenStore
+	tokens  TokenManager
 }
 
-func NewWhatsappService() DefaultWhatsappService {
-	return DefaultWhatsappService{}
+func NewWhatsappService(account *models.WhatsappAccount, store TokenStore, tokens TokenManager) DefaultWhatsappService {
+	return DefaultWhatsappService{account, store, tokens}
 }
 
-func (ws DefaultWhatsappService) SendMessage(body []byte) (http.Header, io.ReadCloser, error) {
+// DefaultWhatsappAccount returns the whatsapp account configured by the
+// environment, that receives webhooks at /wr/receive.
+func DefaultWhatsappAccount() *models.WhatsappAccount {
 	wconfig := config.GetConfig().Whatsapp
+	return &models.WhatsappAccount{
+		Provider:      wconfig.Provider,
+		BaseURL:       wconfig.BaseURL,
+		Username:      wconfig.Username,
+		Password:      wconfig.Password,
+		PhoneNumberID: wconfig.PhoneNumberID,
+		AccessToken:   wconfig.AccessToken,
+		VerifyToken:   wconfig.VerifyToken,
+
+		WebhookSecret:     wconfig.WebhookSecret,
+		WebhookAllowedIPs: wconfig.WebhookAllowedIPs,
+	}
+}
 
-	httpClient := utils.GetHTTPClient()
-
-	reqURL, _ := url.Parse(wconfig.BaseURL + messagePath)
-	req := &http.Request{
-		Method: "POST",
-		URL:    reqURL,
-		Header: map[string][]string{
-			"Content-Type":  {"application/json"},
-			"Accept":        {"application/json"},
-			"Authorization": {"Bearer " + config.GetAuthToken()},
-		},
-		Body: ioutil.NopCloser(bytes.NewReader(body)),
+// NewAccountWhatsappService returns the rate limited whatsapp service of the
+// provider of the account, the token store and manager are only used by
+// on-premises accounts.
+func NewAccountWhatsappService(account *models.WhatsappAccount, store TokenStore, tokens TokenManager) WhatsappService {
+	if account.IsCloud() {
+		return rateLimited(NewCloudWhatsappService(account))
 	}
+	return rateLimited(NewWhatsappService(account, store, tokens))
+}
 
-	res, err := httpClient.Do(req)
+func (ws DefaultWhatsappService) SendMessage(body []byte) (http.Header, io.ReadCloser, error) {
+	res, err := ws.do(func(token string) (*http.Request, error) {
+		reqURL, _ := url.Parse(ws.account.BaseURL + messagePath)
+		return &http.Request{
+			Method: "POST",
+			URL:    reqURL,
+			Header: map[string][]string{
+				"Content-Type":  {"application/json"},
+				"Accept":        {"application/json"},
+				"Authorization": {"Bearer " + token},
+			},
+			Body: ioutil.NopCloser(bytes.NewReader(body)),
+		}, nil
+	})
 
 	if err != nil {
 		return nil, nil, err
 	}
 	if res.StatusCode == 401 {
+		res.Body.Close()
 		return nil, nil, errors.New(res.Status)
 	}
 
@@ -65,76 +97,128 @@ func (ws DefaultWhatsappService) SendMessage(body []byte) (http.Header, io.ReadC
 }
 
 func (ws DefaultWhatsappService) Login() (*http.Response, error) {
-	wconfig := config.GetConfig().Whatsapp
-	httpClient := utils.GetHTTPClient()
-	reqURL, _ := url.Parse(wconfig.BaseURL + loginPath)
-
-	req := &http.Request{
-		Method: "POST",
-		URL:    reqURL,
-		Header: map[string][]string{},
-		Body:   nil,
-	}
+	return whatsappLogin(ws.account)
+}
 
-	req.SetBasicAuth(wconfig.Username, wconfig.Password)
-	return httpClient.Do(req)
+// Account returns the whatsapp account the service sends requests to.
+func (ws DefaultWhatsappService) Account() *models.WhatsappAccount {
+	return ws.account
 }
 
 func (ws DefaultWhatsappService) Health() (*http.Response, error) {
-	wconfig := config.GetConfig().Whatsapp
-	httpClient := utils.GetHTTPClient()
-	reqURL, _ := url.Parse(wconfig.BaseURL + healthPath)
+	return ws.do(func(token string) (*http.Request, error) {
+		reqURL, _ := url.Parse(ws.account.BaseURL + healthPath)
+		return &http.Request{
+			Method: "GET",
+			URL:    reqURL,
+			Header: map[string][]string{
+				"Content-Type":  {"application/json"},
+				"Accept":        {"application/json"},
+				"Authorization": {"Bearer " + token},
+			},
+			Body: nil,
+		}, nil
+	})
+}
 
-	req := &http.Request{
-		Method: "GET",
-		URL:    reqURL,
-		Header: map[string][]string{
-			"Content-Type":  {"application/json"},
-			"Accept":        {"application/json"},
-			"Authorization": {"Bearer " + config.GetAuthToken()},
-		},
-		Body: nil,
+func (ws DefaultWhatsappService) GetMedia(header http.Header, mediaID string) (*http.Response, error) {
+	return ws.do(func(token string) (*http.Request, error) {
+		req, err := http.NewRequest(
+			"GET",
+			ws.account.BaseURL+mediaPath+mediaID,
+			nil,
+		)
+		if err != nil {
+			return nil, err
+		}
+		utils.CopyHeader(req.Header, header)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		return req, nil
+	})
+}
+
+func (ws DefaultWhatsappService) PostMedia(header http.Header, body io.ReadCloser) (*http.Response, error) {
+	// the body is buffered so the request can be sent again on 401
+	media, err := io.ReadAll(body)
+	body.Close()
+	if err != nil {
+		return nil, err
 	}
-	return httpClient.Do(req)
+	return ws.do(func(token string) (*http.Request, error) {
+		req, err := http.NewRequest(
+			"POST",
+			ws.account.BaseURL+mediaPath,
+			bytes.NewReader(media),
+		)
+		if err != nil {
+			return nil, err
+		}
+		utils.CopyHeader(req.Header, header)
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		return req, nil
+	})
 }
 
-func (ws DefaultWhatsappService) GetMedia(header http.Header, mediaID string) (*http.Response, error) {
-	wconfig := config.GetConfig().Whatsapp
+// GetTemplates requests the message templates of the account, only available
+// in business api versions managing templates.
+func (ws DefaultWhatsappService) GetTemplates() (*http.Response, error) {
+	return ws.do(func(token string) (*http.Request, error) {
+		req, err := http.NewRequest("GET", ws.account.BaseURL+templatesPath, nil)
+		if err != nil {
+			return nil, err
+		}
+		req.Header.Set("Accept", "application/json")
+		req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", token))
+		return req, nil
+	})
+}
+
+// do sends the request built with the current auth token. When whatsapp
+// responds 401 the token is refreshed and the request is sent once more.
+func (ws DefaultWhatsappService) do(newRequest func(token string) (*http.Request, error)) (*http.Response, error) {
 	httpClient := utils.GetHTTPClient()
-	req, err := http.NewRequest(
-		"GET",
-		wconfig.BaseURL+mediaPath+mediaID,
-		nil,
-	)
+	token := ws.store.Get()
+	req, err := newRequest(token)
+	if err != nil {
+		return nil, err
+	}
+	res, err := httpClient.Do(req)
+	if err != nil || res.StatusCode != http.StatusUnauthorized {
+		return res, err
+	}
+	io.Copy(io.Discard, res.Body)
+	res.Body.Close()
+
+	token, err = ws.tokens.Refresh(token)
+	if err != nil {
+		return nil, err
+	}
+	req, err = newRequest(token)
 	if err != nil {
 		return nil, err
 	}
-	utils.CopyHeader(req.Header, header)
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.GetAuthToken()))
 	return httpClient.Do(req)
 }
 
-func (ws DefaultWhatsappService) PostMedia(header http.Header, body io.ReadCloser) (*http.Response, error) {
-	wconfig := config.GetConfig().Whatsapp
+func whatsappLogin(account *models.WhatsappAccount) (*http.Response, error) {
 	httpClient := utils.GetHTTPClient()
-	req, err := http.NewRequest(
-		"POST",
-		wconfig.BaseURL+mediaPath,
-		body,
-	)
-	defer body.Close()
-	if err != nil {
-		return nil, err
+	reqURL, _ := url.Parse(account.BaseURL + loginPath)
+
+	req := &http.Request{
+		Method: "POST",
+		URL:    reqURL,
+		Header: map[string][]string{},
+		Body:   nil,
 	}
-	utils.CopyHeader(req.Header, header)
-	req.Header.Set("Authorization", fmt.Sprintf("Bearer %s", config.GetAuthToken()))
+
+	req.SetBasicAuth(account.Username, account.Password)
 	return httpClient.Do(req)
 }
 
 type LoginWhatsapp struct {
 	Users []struct {
-		Token        string
-		ExpiresAfter string
+		Token        string `json:"token"`
+		ExpiresAfter string `json:"expires_after"`
 	}
 	Meta struct {
 		Version   string
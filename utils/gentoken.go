@@ -1,8 +1,11 @@
 package utils
 
 import (
+	crand "crypto/rand"
+	"encoding/base64"
 	"fmt"
 	"math/rand"
+	"regexp"
 	"time"
 )
 
@@ -11,6 +14,12 @@ const sufixLength = 10
 
 const crumb = "weni-demo"
 
+// tokenRegexp matches a token surrounded by any text that is not part of it,
+// ignoring the case of the prefix.
+var tokenRegexp = regexp.MustCompile(
+	fmt.Sprintf(`(?:^|[^0-9A-Za-z_-])(?i:%s)-([0-9A-Za-z_-]{%d})(?:$|[^0-9A-Za-z_-])`,
+		regexp.QuoteMeta(crumb), sufixLength))
+
 func genTokenSufix() string {
 	rand.Seed(time.Now().UTC().UnixNano())
 	b := make([]byte, sufixLength)
@@ -24,3 +33,26 @@ func GenToken() string {
 	sufix := genTokenSufix()
 	return fmt.Sprintf("%s-%s", crumb, sufix)
 }
+
+// credentialLength is the number of random bytes of channel credentials.
+const credentialLength = 32
+
+// GenCredential returns a random credential to authenticate the requests of a
+// channel.
+func GenCredential() string {
+	b := make([]byte, credentialLength)
+	if _, err := crand.Read(b); err != nil {
+		panic(fmt.Sprintf("unable to generate credential: %s", err))
+	}
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// ExtractToken returns the first well formed token found in the text and
+// whether one was found.
+func ExtractToken(text string) (string, bool) {
+	match := tokenRegexp.FindStringSubmatch(text)
+	if match == nil {
+		return "", false
+	}
+	return fmt.Sprintf("%s-%s", crumb, match[1]), true
+}
@@ -34,3 +34,72 @@ func TestGenerateTokens(t *testing.T) {
 		})
 	}
 }
+
+var tcExtractTokens = []struct {
+	TestName string
+	Text     string
+	Token    string
+	Found    bool
+}{
+	{
+		TestName: "Only the token",
+		Text:     "weni-demo-44a2m17t0x",
+		Token:    "weni-demo-44a2m17t0x",
+		Found:    true,
+	},
+	{
+		TestName: "Token surrounded by whitespace",
+		Text:     "  weni-demo-44a2m17t0x\n",
+		Token:    "weni-demo-44a2m17t0x",
+		Found:    true,
+	},
+	{
+		TestName: "Token surrounded by text",
+		Text:     "hi weni-demo-44a2m17t0x, thanks!",
+		Token:    "weni-demo-44a2m17t0x",
+		Found:    true,
+	},
+	{
+		TestName: "Token with upper case prefix",
+		Text:     "Weni-Demo-44a2m17t0x",
+		Token:    "weni-demo-44a2m17t0x",
+		Found:    true,
+	},
+	{
+		TestName: "Token with dash and underscore",
+		Text:     "weni-demo-a-b_c-d_e-",
+		Token:    "weni-demo-a-b_c-d_e-",
+		Found:    true,
+	},
+	{
+		TestName: "Only the prefix",
+		Text:     "what is weni-demo?",
+		Found:    false,
+	},
+	{
+		TestName: "Token too short",
+		Text:     "weni-demo-44a2m",
+		Found:    false,
+	},
+	{
+		TestName: "Token too long",
+		Text:     "weni-demo-44a2m17t0x1",
+		Found:    false,
+	},
+	{
+		TestName: "Prefix inside another word",
+		Text:     "myweni-demo-44a2m17t0x",
+		Found:    false,
+	},
+}
+
+func TestExtractTokens(t *testing.T) {
+	for _, tc := range tcExtractTokens {
+		t.Run(tc.TestName, func(t *testing.T) {
+			token, found := ExtractToken(tc.Text)
+			if found != tc.Found || token != tc.Token {
+				t.Errorf("got %v, %v / want %v, %v", token, found, tc.Token, tc.Found)
+			}
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package utils
+
+// callingCodeLanguages maps country calling codes to the language spoken in
+// the country.
+var callingCodeLanguages = map[string]string{
+	"1":   "en",
+	"44":  "en",
+	"61":  "en",
+	"64":  "en",
+	"353": "en",
+	"27":  "en",
+	"55":  "pt",
+	"351": "pt",
+	"244": "pt",
+	"258": "pt",
+	"34":  "es",
+	"52":  "es",
+	"54":  "es",
+	"56":  "es",
+	"57":  "es",
+	"51":  "es",
+	"58":  "es",
+	"593": "es",
+	"591": "es",
+	"595": "es",
+	"598": "es",
+	"502": "es",
+	"503": "es",
+	"504": "es",
+	"505": "es",
+	"506": "es",
+	"507": "es",
+	"33":  "fr",
+	"32":  "fr",
+	"49":  "de",
+	"43":  "de",
+	"39":  "it",
+}
+
+// LanguageFromURN returns the language of the country of the phone number, or
+// an empty string if it is unknown. Calling codes have up to three digits and
+// none of them is a prefix of another.
+func LanguageFromURN(urn string) string {
+	for i := 1; i <= 3 && i <= len(urn); i++ {
+		if language, ok := callingCodeLanguages[urn[:i]]; ok {
+			return language
+		}
+	}
+	return ""
+}
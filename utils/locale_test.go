@@ -0,0 +1,27 @@
+package utils
+
+import "testing"
+
+var tcLanguageFromURN = []struct {
+	TestName string
+	URN      string
+	Language string
+}{
+	{TestName: "Brazil", URN: "5582988887777", Language: "pt"},
+	{TestName: "Portugal", URN: "351912345678", Language: "pt"},
+	{TestName: "United States", URN: "12025550123", Language: "en"},
+	{TestName: "Mexico", URN: "5215512345678", Language: "es"},
+	{TestName: "Ecuador", URN: "593991234567", Language: "es"},
+	{TestName: "Unknown", URN: "8613812345678", Language: ""},
+	{TestName: "Empty", URN: "", Language: ""},
+}
+
+func TestLanguageFromURN(t *testing.T) {
+	for _, tc := range tcLanguageFromURN {
+		t.Run(tc.TestName, func(t *testing.T) {
+			if language := LanguageFromURN(tc.URN); language != tc.Language {
+				t.Errorf("got %v / want %v", language, tc.Language)
+			}
+		})
+	}
+}
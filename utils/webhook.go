@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+)
+
+const signaturePrefix = "sha256="
+
+// Signature returns the HMAC-SHA256 signature of the body with the secret, in
+// the X-Hub-Signature-256 header format.
+func Signature(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return signaturePrefix + hex.EncodeToString(mac.Sum(nil))
+}
+
+// ValidSignature reports whether the signature is the one of the body with
+// the secret.
+func ValidSignature(secret string, body []byte, signature string) bool {
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return false
+	}
+	return hmac.Equal([]byte(Signature(secret, body)), []byte(signature))
+}
+
+// ParseIPNets parses the ips and networks in CIDR notation of the list, ips
+// are parsed as single address networks.
+func ParseIPNets(list []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range list {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		if ip := net.ParseIP(s); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip4 := ip.To4(); ip4 != nil {
+				ip, bits = ip4, 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, ipNet, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ip or network %q", s)
+		}
+		nets = append(nets, ipNet)
+	}
+	return nets, nil
+}
+
+// AllowedIP reports whether the host of the address is in one of the ips and
+// networks of the allowlist, every address is allowed by an empty allowlist.
+func AllowedIP(addr string, allowlist []string) bool {
+	nets, err := ParseIPNets(allowlist)
+	if err != nil {
+		return false
+	}
+	if len(nets) == 0 {
+		return true
+	}
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	return containsIP(nets, net.ParseIP(host))
+}
+
+// ClientIP returns the ip of the client of the request. Only when the request
+// comes from one of the trusted proxies the X-Forwarded-For header is read,
+// from the last address, skipping the ones of trusted proxies.
+func ClientIP(r *http.Request, trustedProxies []string) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+	nets, err := ParseIPNets(trustedProxies)
+	if err != nil || !containsIP(nets, net.ParseIP(host)) {
+		return host
+	}
+	var forwarded []string
+	for _, header := range r.Header.Values("X-Forwarded-For") {
+		forwarded = append(forwarded, strings.Split(header, ",")...)
+	}
+	for i := len(forwarded) - 1; i >= 0; i-- {
+		addr := strings.TrimSpace(forwarded[i])
+		if !containsIP(nets, net.ParseIP(addr)) {
+			return addr
+		}
+		host = addr
+	}
+	return host
+}
+
+func containsIP(nets []*net.IPNet, ip net.IP) bool {
+	if ip == nil {
+		return false
+	}
+	for _, ipNet := range nets {
+		if ipNet.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,69 @@
+package utils
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidSignature(t *testing.T) {
+	body := []byte(`{"messages":[]}`)
+	signature := Signature("secret", body)
+
+	assert.True(t, ValidSignature("secret", body, signature))
+	assert.False(t, ValidSignature("other-secret", body, signature))
+	assert.False(t, ValidSignature("secret", []byte(`{"messages":[{}]}`), signature))
+	assert.False(t, ValidSignature("secret", body, signature[len("sha256="):]))
+	assert.False(t, ValidSignature("secret", body, ""))
+}
+
+var tcAllowedIPs = []struct {
+	Addr      string
+	Allowlist []string
+	Allowed   bool
+}{
+	{"10.0.0.1:41234", nil, true},
+	{"10.0.0.1:41234", []string{"10.0.0.1"}, true},
+	{"10.0.0.2:41234", []string{"10.0.0.1"}, false},
+	{"10.0.3.7:41234", []string{"192.168.0.1", "10.0.0.0/16"}, true},
+	{"10.1.0.1:41234", []string{"10.0.0.0/16"}, false},
+	{"[2001:db8::1]:41234", []string{"2001:db8::/32"}, true},
+	{"10.0.0.1:41234", []string{"not an ip"}, false},
+}
+
+func TestAllowedIP(t *testing.T) {
+	for _, tc := range tcAllowedIPs {
+		assert.Equal(t, tc.Allowed, AllowedIP(tc.Addr, tc.Allowlist), "%s in %v", tc.Addr, tc.Allowlist)
+	}
+}
+
+var tcClientIPs = []struct {
+	Label          string
+	Addr           string
+	ForwardedFor   []string
+	TrustedProxies []string
+	IP             string
+}{
+	{"no trusted proxies", "10.0.0.1:41234", []string{"203.0.113.7"}, nil, "10.0.0.1"},
+	{"untrusted proxy", "10.0.1.1:41234", []string{"203.0.113.7"}, []string{"10.0.0.0/24"}, "10.0.1.1"},
+	{"trusted proxy", "10.0.0.1:41234", []string{"203.0.113.7"}, []string{"10.0.0.0/24"}, "203.0.113.7"},
+	{"spoofed address before the proxy", "10.0.0.1:41234", []string{"198.51.100.1, 203.0.113.7"}, []string{"10.0.0.0/24"}, "203.0.113.7"},
+	{"chain of trusted proxies", "10.0.0.1:41234", []string{"203.0.113.7, 10.0.0.2", "10.0.0.3"}, []string{"10.0.0.0/24"}, "203.0.113.7"},
+	{"only trusted proxies", "10.0.0.1:41234", []string{"10.0.0.2"}, []string{"10.0.0.0/24"}, "10.0.0.2"},
+	{"trusted proxy without header", "10.0.0.1:41234", nil, []string{"10.0.0.0/24"}, "10.0.0.1"},
+	{"invalid forwarded address", "10.0.0.1:41234", []string{"unknown"}, []string{"10.0.0.0/24"}, "unknown"},
+	{"invalid trusted proxies", "10.0.0.1:41234", []string{"203.0.113.7"}, []string{"not an ip"}, "10.0.0.1"},
+}
+
+func TestClientIP(t *testing.T) {
+	for _, tc := range tcClientIPs {
+		r := httptest.NewRequest(http.MethodPost, "/wr/receive/", nil)
+		r.RemoteAddr = tc.Addr
+		for _, header := range tc.ForwardedFor {
+			r.Header.Add("X-Forwarded-For", header)
+		}
+		assert.Equal(t, tc.IP, ClientIP(r, tc.TrustedProxies), tc.Label)
+	}
+}